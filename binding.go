@@ -0,0 +1,68 @@
+package ginx
+
+import (
+	"fmt"
+	"github.com/paveldanilin/ginx/requestbody"
+	"github.com/paveldanilin/ginx/resolver"
+	"net/http"
+)
+
+// BindingDetails describes a request value which could not be bound to a handler argument.
+type BindingDetails struct {
+	Scope    string `json:"scope" xml:"scope"`
+	Variable string `json:"variable,omitempty" xml:"variable,omitempty"`
+	Value    string `json:"value,omitempty" xml:"value,omitempty"`
+	Type     string `json:"type" xml:"type"`
+}
+
+// bindingHTTPError converts the binding error into 400 Bad Request,
+// so client mistakes are not reported as server faults.
+func bindingHTTPError(err *resolver.BindingError) *HTTPError {
+	message := "invalid request body"
+	if err.Scope != resolver.ScopeBody {
+		message = fmt.Sprintf("invalid %s variable '%s'", err.Scope, err.Variable)
+	}
+
+	return NewHTTPError(http.StatusBadRequest, message).
+		WithCode("binding_error").
+		WithDetails(BindingDetails{
+			Scope:    string(err.Scope),
+			Variable: err.Variable,
+			Value:    err.Value,
+			Type:     err.Type.String(),
+		}).
+		Wrap(err)
+}
+
+// MissingDetails describes an absent required request variable.
+type MissingDetails struct {
+	Scope    string `json:"scope" xml:"scope"`
+	Variable string `json:"variable" xml:"variable"`
+}
+
+// missingHTTPError converts the missing variables error into 400 Bad Request listing all missing variables.
+func missingHTTPError(err *resolver.MissingError) *HTTPError {
+	details := make([]MissingDetails, 0, len(err.Variables))
+	for _, v := range err.Variables {
+		details = append(details, MissingDetails{Scope: string(v.Scope), Variable: v.Name})
+	}
+
+	return NewHTTPError(http.StatusBadRequest, "missing required variables").
+		WithCode("missing_variables").
+		WithDetails(details).
+		Wrap(err)
+}
+
+// fileLimitHTTPError responds 413 Request Entity Too Large with the exceeded file limit.
+func fileLimitHTTPError(err *resolver.FileLimitError) *HTTPError {
+	return NewHTTPError(http.StatusRequestEntityTooLarge, err.Error()).
+		WithCode("file_limit_exceeded").
+		Wrap(err)
+}
+
+// unsupportedMediaTypeHTTPError responds 415 Unsupported Media Type with the unsupported body format.
+func unsupportedMediaTypeHTTPError(err *requestbody.UnsupportedMediaTypeError) *HTTPError {
+	return NewHTTPError(http.StatusUnsupportedMediaType, err.Error()).
+		WithCode("unsupported_media_type").
+		Wrap(err)
+}
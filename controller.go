@@ -1,11 +1,14 @@
 package ginx
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/paveldanilin/ginx/resolver"
 	"github.com/paveldanilin/ginx/slices"
+	"net/http"
 	"reflect"
 	"runtime"
 	"strings"
@@ -16,31 +19,41 @@ type Option any
 type Controller struct {
 	BasePath          string
 	ContentType       string
-	router            *gin.Engine
+	router            *gin.RouterGroup
+	parent            *Controller
 	handlerMap        map[string]*handler
 	argumentResolvers []ArgumentResolver
 	middlewares       []gin.HandlerFunc
 	tester            *Tester
 	errorInterceptor  ErrorInterceptor
+	renderers         rendererMap
+	validator         *validator.Validate
+	errorMappings     []errorMapping
+	problemDetails    *problemDetails
+	services          *container
 }
 
 func NewController(r *gin.Engine) *Controller {
 	return &Controller{
-		router:            r,
+		router:            &r.RouterGroup,
 		handlerMap:        map[string]*handler{},
 		argumentResolvers: []ArgumentResolver{},
 		middlewares:       []gin.HandlerFunc{},
 		tester:            NewTester(r),
+		renderers:         rendererMap{},
+		validator:         newValidator(),
 	}
 }
 
 func NewDefaultController(r *gin.Engine) *Controller {
 	c := &Controller{
-		router:            r,
+		router:            &r.RouterGroup,
 		handlerMap:        map[string]*handler{},
 		argumentResolvers: []ArgumentResolver{},
 		middlewares:       []gin.HandlerFunc{},
 		tester:            NewTester(r),
+		renderers:         rendererMap{},
+		validator:         newValidator(),
 	}
 
 	// HttpRequest creates a resolver which can inject *http.Request into user handler argument.
@@ -51,12 +64,22 @@ func NewDefaultController(r *gin.Engine) *Controller {
 	c.Use(resolver.Context())
 	// Bind request body into struct, bind query/header/path values by tag 'ginx'.
 	c.Use(resolver.Struct())
+	// Bind arguments embedding ginx.Path, ginx.Query or ginx.Header.
+	c.Use(Params())
+	// LastEventIDResolver creates a resolver which can inject the Last-Event-ID header into ginx.LastEventID argument.
+	c.Use(LastEventIDResolver())
 
 	return c
 }
 
 func (c *Controller) Use(opt Option) {
 	if r, isResolver := opt.(ArgumentResolver); isResolver {
+		for i, used := range c.argumentResolvers {
+			if replaces(r, used) {
+				c.argumentResolvers[i] = r
+				return
+			}
+		}
 		c.argumentResolvers = append(c.argumentResolvers, r)
 		return
 	}
@@ -70,6 +93,37 @@ func (c *Controller) Use(opt Option) {
 		c.errorInterceptor = i
 		return
 	}
+
+	if r, isRenderer := opt.(Renderer); isRenderer {
+		c.renderers.add(r)
+		return
+	}
+
+	if p, isProblemDetails := opt.(*problemDetails); isProblemDetails {
+		c.problemDetails = p
+		return
+	}
+
+	if m, isErrorMapping := opt.(errorMapping); isErrorMapping {
+		c.errorMappings = append(c.errorMappings, m)
+		return
+	}
+
+	if p, isProvider := opt.(*provider); isProvider {
+		if c.services == nil {
+			c.services = newContainer(c)
+			c.argumentResolvers = append(c.argumentResolvers, c.services)
+		}
+		c.services.add(p)
+		return
+	}
+
+	if v, isValidation := opt.(validation); isValidation {
+		if err := c.validator.RegisterValidation(v.tag, v.fn); err != nil {
+			panic(err)
+		}
+		return
+	}
 }
 
 func (c *Controller) GET(path string, handler HandlerFunc, opts ...HandlerOption) error {
@@ -100,80 +154,176 @@ func (c *Controller) OPTIONS(path string, handler HandlerFunc, opts ...HandlerOp
 	return c.registerHandler("OPTIONS", path, handler, opts...)
 }
 
+// MustGET is like GET but panics if the handler can not be registered.
+func (c *Controller) MustGET(path string, handler HandlerFunc, opts ...HandlerOption) {
+	c.mustRegisterHandler("GET", path, handler, opts...)
+}
+
+// MustPOST is like POST but panics if the handler can not be registered.
+func (c *Controller) MustPOST(path string, handler HandlerFunc, opts ...HandlerOption) {
+	c.mustRegisterHandler("POST", path, handler, opts...)
+}
+
+// MustPUT is like PUT but panics if the handler can not be registered.
+func (c *Controller) MustPUT(path string, handler HandlerFunc, opts ...HandlerOption) {
+	c.mustRegisterHandler("PUT", path, handler, opts...)
+}
+
+// MustPATCH is like PATCH but panics if the handler can not be registered.
+func (c *Controller) MustPATCH(path string, handler HandlerFunc, opts ...HandlerOption) {
+	c.mustRegisterHandler("PATCH", path, handler, opts...)
+}
+
+// MustDELETE is like DELETE but panics if the handler can not be registered.
+func (c *Controller) MustDELETE(path string, handler HandlerFunc, opts ...HandlerOption) {
+	c.mustRegisterHandler("DELETE", path, handler, opts...)
+}
+
+// MustHEAD is like HEAD but panics if the handler can not be registered.
+func (c *Controller) MustHEAD(path string, handler HandlerFunc, opts ...HandlerOption) {
+	c.mustRegisterHandler("HEAD", path, handler, opts...)
+}
+
+// MustOPTIONS is like OPTIONS but panics if the handler can not be registered.
+func (c *Controller) MustOPTIONS(path string, handler HandlerFunc, opts ...HandlerOption) {
+	c.mustRegisterHandler("OPTIONS", path, handler, opts...)
+}
+
 func (c *Controller) Tester() *Tester {
 	return c.tester
 }
 
 func (c *Controller) registerHandler(method, path string, handlerFunc HandlerFunc, opts ...HandlerOption) error {
+	h, err := c.newHandler(method, path, handlerFunc)
+	if err != nil {
+		return err
+	}
+
+	h.init(c.allArgumentResolvers(), opts...)
+
+	return c.addHandler(h, opts...)
+}
+
+// newHandler creates a handler definition for the function served by the method and path relative to the controller.
+func (c *Controller) newHandler(method, path string, handlerFunc HandlerFunc) (*handler, error) {
 	handlerFuncReflect := reflect.ValueOf(handlerFunc)
 	if handlerFuncReflect.Kind() != reflect.Func {
-		return errors.New("handler must be function")
+		return nil, errors.New("handler must be function")
 	}
 
 	method = normalizeHttpMethod(method)
 	path = normalizePath(path)
 
-	h := &handler{
-		name:      getGoMethodName(handlerFuncReflect.Pointer()),
-		numIn:     handlerFuncReflect.Type().NumIn(),
-		numOut:    handlerFuncReflect.Type().NumOut(),
-		function:  handlerFuncReflect,
-		arguments: []reflect.Type{},
-		resolvers: []ArgumentResolver{},
+	if !isSupportedHttpMethod(method) {
+		return nil, fmt.Errorf("unknown method '%s'", method)
 	}
 
-	h.init(c.argumentResolvers, opts...)
+	return &handler{
+		controller:   c,
+		name:         getGoMethodName(handlerFuncReflect.Pointer()),
+		method:       method,
+		path:         joinPaths(c.router.BasePath(), c.BasePath+path),
+		relativePath: c.BasePath + path,
+		numIn:        handlerFuncReflect.Type().NumIn(),
+		numOut:       handlerFuncReflect.Type().NumOut(),
+		function:     handlerFuncReflect,
+		arguments:    []reflect.Type{},
+		resolvers:    []ArgumentResolver{},
+	}, nil
+}
 
-	c.handlerMap[getHandlerId(method, c.BasePath+path)] = h
+// addHandler verifies the initialized handler and registers it in the router.
+func (c *Controller) addHandler(h *handler, opts ...HandlerOption) error {
+	if err := h.verify(); err != nil {
+		return fmt.Errorf("%s %s: %w", h.method, h.path, err)
+	}
+	if err := c.verifyValidationRules(h); err != nil {
+		return fmt.Errorf("%s %s: %w", h.method, h.path, err)
+	}
 
-	// [<controller.middlewares>, <handler.middlewares>, <request.handler>]
-	var ginHandlers []gin.HandlerFunc = slices.Join(c.middlewares, handlerOptions(opts).Middlewares())
-	ginHandlers = append(ginHandlers, c.handleRequest)
+	c.handlerMap[getHandlerId(h.method, h.path)] = h
 
-	fullPath := c.BasePath + path
+	// [<controller.middlewares>, <handler.middlewares>, <request.handler>]
+	var ginHandlers []gin.HandlerFunc = slices.Join(c.allMiddlewares(), handlerOptions(opts).Middlewares())
+	serve := c.handleRequest
+	if h.websocket {
+		serve = c.handleWebSocket
+	}
+	ginHandlers = append(ginHandlers, func(ctx *gin.Context) {
+		serve(ctx, h)
+	})
 
-	switch method {
+	switch h.method {
 	case "GET":
-		c.router.GET(fullPath, ginHandlers...)
+		c.router.GET(h.relativePath, ginHandlers...)
 	case "POST":
-		c.router.POST(fullPath, ginHandlers...)
+		c.router.POST(h.relativePath, ginHandlers...)
 	case "PUT":
-		c.router.PUT(fullPath, ginHandlers...)
+		c.router.PUT(h.relativePath, ginHandlers...)
 	case "PATCH":
-		c.router.PATCH(fullPath, ginHandlers...)
+		c.router.PATCH(h.relativePath, ginHandlers...)
 	case "DELETE":
-		c.router.DELETE(fullPath, ginHandlers...)
+		c.router.DELETE(h.relativePath, ginHandlers...)
 	case "HEAD":
-		c.router.HEAD(fullPath, ginHandlers...)
+		c.router.HEAD(h.relativePath, ginHandlers...)
 	case "OPTIONS":
-		c.router.OPTIONS(fullPath, ginHandlers...)
+		c.router.OPTIONS(h.relativePath, ginHandlers...)
 	}
 
-	return errors.New("unknown method")
+	return nil
 }
 
-func (c *Controller) handleRequest(ctx *gin.Context) {
+func (c *Controller) mustRegisterHandler(method, path string, handlerFunc HandlerFunc, opts ...HandlerOption) {
+	if err := c.registerHandler(method, path, handlerFunc, opts...); err != nil {
+		panic(err)
+	}
+}
+
+func (c *Controller) handleRequest(ctx *gin.Context, h *handler) {
 	defer func() {
 		if err := recover(); err != nil {
 			c.handlePanic(ctx, err)
 		}
 	}()
 
-	h := c.getHandler(ctx)
-	if h == nil {
-		panic("handler not found")
-	}
-
 	ctx.Set("ginx_handler_name", h.name)
-	ctx.Set("ginx_controller_response_type", c.ContentType)
+	ctx.Set("ginx_controller_response_type", c.getContentType())
 	ctx.Set("ginx_handler_response_type", h.responseContentType)
+	if h.heartbeat != nil {
+		ctx.Set("ginx_heartbeat", *h.heartbeat)
+	}
+
+	// Only handlers declaring Produces negotiate the content type,
+	// others respond with the configured content type whatever the client accepts (RFC 7231 section 5.3.2).
+	if len(h.produces) > 0 {
+		ctx.Header("Vary", "Accept")
+		contentType, acceptable := negotiateContentType(ctx.GetHeader("Accept"), h.produces)
+		if !acceptable {
+			c.handleError(ctx, ErrNotAcceptable)
+			return
+		}
+		ctx.Set("ginx_negotiated_response_type", contentType)
+	}
+
+	// Typed handlers are called directly, see Handle.
+	if h.invoke != nil {
+		res, err := h.invoke(ctx)
+		if err != nil {
+			c.handleError(ctx, err)
+			return
+		}
+		c.sendResponse(ctx, res)
+		return
+	}
 
-	hArgs, err := h.resolveArguments(ctx)
+	hArgs, hResolvers, err := h.resolveArguments(ctx)
 	if err != nil {
 		panic(err)
 	}
 
-	// TODO: validate arguments
+	if err := c.validateArguments(hArgs, hResolvers); err != nil {
+		panic(err)
+	}
 
 	handlerResponse := h.function.Call(hArgs)
 
@@ -181,11 +331,34 @@ func (c *Controller) handleRequest(ctx *gin.Context) {
 }
 
 func (c *Controller) handlePanic(ctx *gin.Context, err any) {
-	e := newError(ctx, err)
-	if c.errorInterceptor != nil {
-		c.errorInterceptor.InterceptError(e)
+	// The response is partly written already, so net/http closes the connection, see streamResponse.
+	if err == http.ErrAbortHandler {
+		panic(err)
+	}
+	c.handleError(ctx, err)
+}
+
+func (c *Controller) handleError(ctx *gin.Context, err any) {
+	e := c.newError(ctx, err)
+	if i := c.getErrorInterceptor(); i != nil {
+		i.InterceptError(e)
 	}
-	c.sendResponse(ctx, e.Response())
+
+	res := e.Response()
+	if c.isUnrenderable(ctx, res) {
+		// Error bodies are structured, so they are kept machine-readable if the content type has no renderer.
+		res.SetContentType(gin.MIMEJSON)
+	}
+	c.sendResponse(ctx, res)
+}
+
+// isUnrenderable reports whether the response body is neither text nor bytes and there is no renderer for it.
+func (c *Controller) isUnrenderable(ctx *gin.Context, res Response) bool {
+	switch res.Body().(type) {
+	case nil, string, []byte:
+		return false
+	}
+	return c.findRenderer(c.getResponseContentType(ctx, res)) == nil
 }
 
 func (c *Controller) response(ctx *gin.Context, handlerResponse []reflect.Value) {
@@ -196,11 +369,7 @@ func (c *Controller) response(ctx *gin.Context, handlerResponse []reflect.Value)
 	case 1:
 		// If user handler returns: (<error>)
 		if isError(handlerResponse[0]) {
-			e := newError(ctx, handlerResponse[0])
-			if c.errorInterceptor != nil {
-				c.errorInterceptor.InterceptError(e)
-			}
-			c.sendResponse(ctx, e.Response())
+			c.handleError(ctx, handlerResponse[0].Interface())
 			return
 		}
 
@@ -211,20 +380,12 @@ func (c *Controller) response(ctx *gin.Context, handlerResponse []reflect.Value)
 	default:
 		// If user handler returns: (<userdata>, <error>)
 		if isError(handlerResponse[1]) {
-			e := newError(ctx, handlerResponse[1].Interface())
-			if c.errorInterceptor != nil {
-				c.errorInterceptor.InterceptError(e)
-			}
-			c.sendResponse(ctx, e.Response())
+			c.handleError(ctx, handlerResponse[1].Interface())
 			return
 		}
 		// If user handler returns: (<error>, <userdata>)
 		if isError(handlerResponse[0]) {
-			e := newError(ctx, handlerResponse[0].Interface())
-			if c.errorInterceptor != nil {
-				c.errorInterceptor.InterceptError(e)
-			}
-			c.sendResponse(ctx, e.Response())
+			c.handleError(ctx, handlerResponse[0].Interface())
 			return
 		}
 
@@ -241,8 +402,31 @@ func (c *Controller) response(ctx *gin.Context, handlerResponse []reflect.Value)
 }
 
 func (c *Controller) sendResponse(ctx *gin.Context, res Response) {
+	for name, values := range responseHeader(res) {
+		for _, v := range values {
+			ctx.Writer.Header().Add(name, v)
+		}
+	}
+	for _, cookie := range res.Cookies() {
+		http.SetCookie(ctx.Writer, cookie)
+	}
+
+	// 204 No Content and 304 Not Modified responses must not have a body.
+	if res.Status() == http.StatusNoContent || res.Status() == http.StatusNotModified {
+		ctx.Status(res.Status())
+		return
+	}
+
+	if c.serveFile(ctx, res) {
+		return
+	}
+
 	responseContentType := c.getResponseContentType(ctx, res)
 
+	if c.streamResponse(ctx, res, responseContentType) {
+		return
+	}
+
 	if s, isString := res.Body().(string); isString {
 		ctx.Data(res.Status(), responseContentType, []byte(s))
 		return
@@ -253,16 +437,38 @@ func (c *Controller) sendResponse(ctx *gin.Context, res Response) {
 		return
 	}
 
-	switch getFormat(responseContentType) {
-	case "json":
-		ctx.JSON(res.Status(), res.Body())
-	case "xml":
-		ctx.XML(res.Status(), res.Body())
-	case "text":
-		ctx.Data(res.Status(), responseContentType, []byte(fmt.Sprintf("%v", res.Body())))
-	default:
+	r := c.findRenderer(responseContentType)
+	if r == nil {
 		ctx.Data(res.Status(), responseContentType, []byte(fmt.Sprintf("%v", res.Body())))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := r.Render(&buf, res.Body()); err != nil {
+		_ = ctx.Error(err)
+		ctx.Data(http.StatusInternalServerError, gin.MIMEPlain, []byte(http.StatusText(http.StatusInternalServerError)))
+		return
+	}
+
+	ctx.Data(res.Status(), withCharset(responseContentType), buf.Bytes())
+}
+
+// responseHeader returns the response header, the built-in response does not allocate it if there are no headers.
+func responseHeader(res Response) http.Header {
+	if r, isResponse := res.(*response); isResponse {
+		return r.header
+	}
+	return res.Header()
+}
+
+// findRenderer returns a renderer registered for the content type, falls back to the default renderers.
+func (c *Controller) findRenderer(contentType string) Renderer {
+	for owner := c; owner != nil; owner = owner.parent {
+		if r := owner.renderers.find(contentType); r != nil {
+			return r
+		}
 	}
+	return defaultRenderers.find(contentType)
 }
 
 func (c *Controller) getResponseContentType(ctx *gin.Context, res Response) string {
@@ -270,6 +476,11 @@ func (c *Controller) getResponseContentType(ctx *gin.Context, res Response) stri
 		return res.ContentType()
 	}
 
+	negotiatedContentType, existsNegotiatedContentType := ctx.Get("ginx_negotiated_response_type")
+	if existsNegotiatedContentType && strings.TrimSpace(negotiatedContentType.(string)) != "" {
+		return negotiatedContentType.(string)
+	}
+
 	handlerResponseContentType, existsHandlerContentType := ctx.Get("ginx_handler_response_type")
 	if existsHandlerContentType && strings.TrimSpace(handlerResponseContentType.(string)) != "" {
 		return handlerResponseContentType.(string)
@@ -280,14 +491,23 @@ func (c *Controller) getResponseContentType(ctx *gin.Context, res Response) stri
 		return controllerResponseContentType.(string)
 	}
 
-	// TODO: get content type by request header
-
 	return gin.MIMEPlain
 }
 
-func (c *Controller) getHandler(ctx *gin.Context) *handler {
-	if h, exists := c.handlerMap[getHandlerId(ctx.Request.Method, ctx.FullPath())]; exists {
-		return h
+// producibleContentTypes returns content types the handler can respond with, in the preference order.
+func (c *Controller) producibleContentTypes(h *handler) []string {
+	if len(h.produces) > 0 {
+		return h.produces
+	}
+	if strings.TrimSpace(h.responseContentType) != "" {
+		return []string{h.responseContentType}
+	}
+	// Files are served by their own content type, the controller content type does not apply.
+	if h.servesFile {
+		return nil
+	}
+	if contentType := c.getContentType(); strings.TrimSpace(contentType) != "" {
+		return []string{contentType}
 	}
 	return nil
 }
@@ -300,6 +520,14 @@ func normalizeHttpMethod(method string) string {
 	return strings.ToUpper(strings.TrimSpace(method))
 }
 
+func isSupportedHttpMethod(method string) bool {
+	switch method {
+	case "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
 func normalizePath(path string) string {
 	path = strings.TrimSpace(path)
 	if path == "" {
@@ -312,19 +540,6 @@ func getHandlerId(method, path string) string {
 	return method + path
 }
 
-func getFormat(contentType string) string {
-	if strings.Contains(contentType, "json") {
-		return "json"
-	}
-	if strings.Contains(contentType, "xml") {
-		return "xml"
-	}
-	if strings.Contains(contentType, "text") {
-		return "text"
-	}
-	return "text"
-}
-
 var errType = reflect.TypeOf((*error)(nil)).Elem()
 
 func isError(v reflect.Value) bool {
@@ -335,15 +550,19 @@ func isError(v reflect.Value) bool {
 }
 
 func getStatus(v reflect.Value) (int, bool) {
-	if v.Kind() == reflect.Int ||
-		v.Kind() == reflect.Int16 ||
-		v.Kind() == reflect.Int32 ||
-		v.Kind() == reflect.Int64 ||
-		v.Kind() == reflect.Uint ||
-		v.Kind() == reflect.Uint16 ||
-		v.Kind() == reflect.Uint32 ||
-		v.Kind() == reflect.Uint64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64:
 		return int(v.Int()), true
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()), true
 	}
 	return 0, false
 }
+
+func isStatusType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
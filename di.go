@@ -0,0 +1,300 @@
+package ginx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"reflect"
+	"sync"
+)
+
+var ginContextType = reflect.TypeOf((*gin.Context)(nil))
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+type lifetime int
+
+const (
+	singleton lifetime = iota
+	perRequest
+	transient
+)
+
+func (l lifetime) String() string {
+	switch l {
+	case perRequest:
+		return "per-request"
+	case transient:
+		return "transient"
+	}
+	return "singleton"
+}
+
+// provider describes how a service is constructed and how long the constructed instance lives.
+type provider struct {
+	serviceType reflect.Type
+	constructor reflect.Value
+	lifetime    lifetime
+	// requestKey is a gin.Context key of the per-request instance.
+	requestKey string
+
+	mu       sync.Mutex
+	instance reflect.Value
+}
+
+// Provide registers a service which can be injected into handler arguments by its type.
+// The constructor is a function returning the service, optionally with an error, it is called once by default.
+// Constructor arguments are services too; per-request and transient constructors
+// can also accept *gin.Context and context.Context, singletons can depend on singletons only.
+// A value which is not a function is registered as a ready singleton.
+// Services are provided before the handlers taking them are registered, providing a service taken by a handler
+// registered earlier panics, as its argument is already bound by another resolver.
+//
+//	controller.Use(ginx.Provide(openDB))
+//	controller.Use(ginx.Provide(NewUserRepository))
+//	controller.Use(ginx.Provide(NewRequestLogger).PerRequest())
+//
+//	controller.GET("/users/:id", func(id int, users *UserRepository) (*User, error) {
+//		return users.Find(id)
+//	}, resolver.Path("id", 1))
+func Provide(constructor any) *provider {
+	v := reflect.ValueOf(constructor)
+	if !v.IsValid() {
+		panic(errors.New("service must not be nil"))
+	}
+	if (v.Kind() == reflect.Func || v.Kind() == reflect.Pointer) && v.IsNil() {
+		panic(fmt.Errorf("service %s must not be nil", v.Type()))
+	}
+
+	if v.Kind() != reflect.Func {
+		return &provider{serviceType: v.Type(), lifetime: singleton, instance: v}
+	}
+
+	t := v.Type()
+	if t.NumOut() == 0 || t.NumOut() > 2 || t.NumOut() == 2 && t.Out(1) != errType {
+		panic(fmt.Errorf("constructor %s must return (<service>) or (<service>, error)", t))
+	}
+
+	p := &provider{serviceType: t.Out(0), constructor: v, lifetime: singleton}
+	p.requestKey = fmt.Sprintf("ginx_service_%p", p)
+	return p
+}
+
+// PerRequest creates the service once per request.
+func (p *provider) PerRequest() *provider {
+	p.lifetime = perRequest
+	return p
+}
+
+// Transient creates the service every time it is injected.
+func (p *provider) Transient() *provider {
+	p.lifetime = transient
+	return p
+}
+
+// verify checks that a singleton does not depend on a request.
+func (p *provider) verify() error {
+	if !p.constructor.IsValid() || p.lifetime != singleton {
+		return nil
+	}
+	for i := 0; i < p.constructor.Type().NumIn(); i++ {
+		if in := p.constructor.Type().In(i); in == ginContextType || in == contextType {
+			return fmt.Errorf("singleton %s can not depend on the request %s", p.serviceType, in)
+		}
+	}
+	return nil
+}
+
+// container is an argument resolver injecting services provided to the controller and its ancestors.
+type container struct {
+	owner     *Controller
+	providers map[reflect.Type]*provider
+	// order lists providers in the registration order, so lifetimes are verified deterministically.
+	order []*provider
+}
+
+func newContainer(owner *Controller) *container {
+	return &container{owner: owner, providers: map[reflect.Type]*provider{}}
+}
+
+func (s *container) add(p *provider) {
+	if err := p.verify(); err != nil {
+		panic(err)
+	}
+	s.providers[p.serviceType] = p
+	s.order = append(s.order, p)
+
+	if err := s.verifyLifetimes(); err != nil {
+		panic(err)
+	}
+	if err := s.verifyHandlers(p); err != nil {
+		panic(err)
+	}
+}
+
+// verifyHandlers checks that no handler of the owner and its descendants registered earlier takes the service,
+// its argument is bound by another resolver, i.e. resolver.Struct.
+func (s *container) verifyHandlers(p *provider) error {
+	for _, h := range s.owner.handlerMap {
+		if !h.controller.descendsFrom(s.owner) {
+			continue
+		}
+		for i, argumentType := range h.arguments {
+			if argumentType == p.serviceType && h.plan[i] != s {
+				return fmt.Errorf("service %s is provided after the handler of %s %s taking it is registered", p.serviceType, h.method, h.path)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyLifetimes checks that singletons of the container do not reach per-request or transient services,
+// a singleton would keep the instance constructed for the first request.
+// Services provided to ancestors later are checked when the singleton is constructed.
+func (s *container) verifyLifetimes() error {
+	for _, p := range s.order {
+		if p.lifetime != singleton || s.providers[p.serviceType] != p {
+			continue
+		}
+		if err := s.verifySingletonDependencies(p, p, map[reflect.Type]bool{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *container) verifySingletonDependencies(root, p *provider, visited map[reflect.Type]bool) error {
+	if !p.constructor.IsValid() {
+		return nil
+	}
+	for i := 0; i < p.constructor.Type().NumIn(); i++ {
+		in := p.constructor.Type().In(i)
+		dependency, exists := s.owner.findProvider(in)
+		if !exists || visited[in] {
+			continue
+		}
+		visited[in] = true
+
+		if dependency.lifetime != singleton {
+			return singletonDependencyError(root, dependency)
+		}
+		if err := s.verifySingletonDependencies(root, dependency, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func singletonDependencyError(p, dependency *provider) error {
+	return fmt.Errorf("singleton %s can not depend on the %s service %s", p.serviceType, dependency.lifetime, dependency.serviceType)
+}
+
+func (s *container) Priority() int {
+	return 300
+}
+
+func (s *container) Static() bool {
+	return true
+}
+
+func (s *container) CanResolve(_ *gin.Context, argumentType reflect.Type, _ int) bool {
+	_, exists := s.owner.findProvider(argumentType)
+	return exists
+}
+
+func (s *container) Resolve(ctx *gin.Context, argumentType reflect.Type) (reflect.Value, error) {
+	return s.resolve(ctx, argumentType, map[reflect.Type]bool{})
+}
+
+// resolve returns an instance of the service, resolving tracks services being constructed to detect cycles.
+func (s *container) resolve(ctx *gin.Context, serviceType reflect.Type, resolving map[reflect.Type]bool) (reflect.Value, error) {
+	p, exists := s.owner.findProvider(serviceType)
+	if !exists {
+		return reflect.Value{}, fmt.Errorf("service %s is not provided", serviceType)
+	}
+	if resolving[serviceType] {
+		return reflect.Value{}, fmt.Errorf("service %s depends on itself", serviceType)
+	}
+
+	switch p.lifetime {
+	case singleton:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		// A failed construction is retried by the next request.
+		if !p.instance.IsValid() {
+			instance, err := s.construct(ctx, p, resolving)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			p.instance = instance
+		}
+		return p.instance, nil
+	case perRequest:
+		if instance, exists := ctx.Get(p.requestKey); exists {
+			return instance.(reflect.Value), nil
+		}
+		instance, err := s.construct(ctx, p, resolving)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ctx.Set(p.requestKey, instance)
+		return instance, nil
+	}
+
+	return s.construct(ctx, p, resolving)
+}
+
+func (s *container) construct(ctx *gin.Context, p *provider, resolving map[reflect.Type]bool) (reflect.Value, error) {
+	resolving[p.serviceType] = true
+	defer delete(resolving, p.serviceType)
+
+	t := p.constructor.Type()
+	args := make([]reflect.Value, t.NumIn())
+	for i := range args {
+		switch t.In(i) {
+		case ginContextType:
+			args[i] = reflect.ValueOf(ctx)
+		case contextType:
+			args[i] = reflect.ValueOf(ctx.Request.Context())
+		default:
+			if dependency, exists := s.owner.findProvider(t.In(i)); exists && p.lifetime == singleton && dependency.lifetime != singleton {
+				return reflect.Value{}, singletonDependencyError(p, dependency)
+			}
+			arg, err := s.resolve(ctx, t.In(i), resolving)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("could not construct %s: %w", p.serviceType, err)
+			}
+			args[i] = arg
+		}
+	}
+
+	out := p.constructor.Call(args)
+	if len(out) == 2 && !out[1].IsNil() {
+		return reflect.Value{}, fmt.Errorf("could not construct %s: %w", p.serviceType, out[1].Interface().(error))
+	}
+	return out[0], nil
+}
+
+// descendsFrom reports whether the controller is the ancestor or its descendant.
+func (c *Controller) descendsFrom(ancestor *Controller) bool {
+	for owner := c; owner != nil; owner = owner.parent {
+		if owner == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// findProvider looks up the service provider of the controller and its ancestors.
+func (c *Controller) findProvider(serviceType reflect.Type) (*provider, bool) {
+	for owner := c; owner != nil; owner = owner.parent {
+		if owner.services == nil {
+			continue
+		}
+		if p, exists := owner.services.providers[serviceType]; exists {
+			return p, true
+		}
+	}
+	return nil, false
+}
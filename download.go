@@ -0,0 +1,246 @@
+package ginx
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/paveldanilin/ginx/slices"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sniffLength is the number of leading bytes used to detect the content type, see mimetype.Detect.
+const sniffLength = 3072
+
+var fileType = reflect.TypeOf(File{})
+var attachmentType = reflect.TypeOf(Attachment{})
+var fsFileType = reflect.TypeOf(FSFile{})
+
+// File is a response serving the file from the disk.
+// The content type is detected by the file extension or content, range and conditional requests are supported.
+//
+//	controller.GET("/reports/:id", func(id reportID) ginx.File {
+//		return ginx.File{Path: reports.PathOf(id.Value), Name: "report.pdf"}
+//	})
+//
+// The path is not sanitized, use FSFile with os.DirFS to serve files by names sent by clients.
+type File struct {
+	Path string
+	// Name is the file name suggested to the client for saving, the file is displayed inline if the name is empty.
+	Name string
+}
+
+// Attachment is a response serving the content of the reader as a downloaded file.
+// Range requests are supported if the reader implements io.ReadSeeker, the reader is closed if it implements io.Closer.
+//
+//	controller.GET("/exports/users", func(ctx context.Context) (ginx.Attachment, error) {
+//		r, err := exports.Users(ctx)
+//		return ginx.Attachment{Reader: r, Name: "users.csv"}, err
+//	})
+type Attachment struct {
+	Reader io.Reader
+	// Name is the file name suggested to the client for saving.
+	Name string
+	// Size is the content length, zero if unknown. The size of seekable readers is found by seeking.
+	Size int64
+	// ModTime is the last modification time used by conditional requests, zero if unknown.
+	ModTime time.Time
+}
+
+// FSFile is a response serving the file from the file system, i.e. embed.FS or os.DirFS.
+// The path is cleaned, so it can not refer outside the file system, directories are served by their index.html.
+type FSFile struct {
+	FS   fs.FS
+	Path string
+	// Name is the file name suggested to the client for saving, the file is displayed inline if the name is empty.
+	Name string
+}
+
+// staticFilePath is the path of a static file relative to the served file system.
+type staticFilePath string
+
+// Static registers a GET handler serving files of the file system under the relative path.
+//
+//	//go:embed assets
+//	var assets embed.FS
+//
+//	controller.Static("/assets", assets)
+func (c *Controller) Static(relativePath string, fsys fs.FS, opts ...HandlerOption) error {
+	return c.GET(strings.TrimSuffix(relativePath, "/")+"/*filepath", func(filePath staticFilePath) FSFile {
+		return FSFile{FS: fsys, Path: string(filePath)}
+	}, slices.Join(opts, []HandlerOption{resolver.PathFor[staticFilePath]("filepath")})...)
+}
+
+// isFileType reports whether the type is served as a file, see File, Attachment and FSFile.
+func isFileType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t == fileType || t == attachmentType || t == fsFileType
+}
+
+// serveFile serves the file response body and reports whether the body is a file.
+func (c *Controller) serveFile(ctx *gin.Context, res Response) bool {
+	var err error
+
+	switch f := res.Body().(type) {
+	case File:
+		err = serveOSFile(ctx, res, f)
+	case FSFile:
+		err = serveFSFile(ctx, res, f)
+	case Attachment:
+		err = serveAttachment(ctx, res, f)
+	default:
+		return false
+	}
+
+	if err != nil {
+		c.handleError(ctx, err)
+	}
+	return true
+}
+
+func serveOSFile(ctx *gin.Context, res Response, f File) error {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return fileError(err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if stat.IsDir() {
+		return fileError(fs.ErrNotExist)
+	}
+
+	return serveContent(ctx, res, file, filepath.Base(f.Path), f.Name, stat.Size(), stat.ModTime())
+}
+
+func serveFSFile(ctx *gin.Context, res Response, f FSFile) error {
+	name := strings.TrimPrefix(path.Clean("/"+f.Path), "/")
+	if name == "" {
+		name = "."
+	}
+
+	file, err := f.FS.Open(name)
+	if err != nil {
+		return fileError(err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if stat.IsDir() {
+		return serveFSFile(ctx, res, FSFile{FS: f.FS, Path: path.Join(name, "index.html"), Name: f.Name})
+	}
+
+	return serveContent(ctx, res, file, stat.Name(), f.Name, stat.Size(), stat.ModTime())
+}
+
+func serveAttachment(ctx *gin.Context, res Response, a Attachment) error {
+	if a.Reader == nil {
+		return errors.New("attachment has no reader")
+	}
+	if closer, isCloser := a.Reader.(io.Closer); isCloser {
+		defer closer.Close()
+	}
+
+	return serveContent(ctx, res, a.Reader, a.Name, a.Name, a.Size, a.ModTime)
+}
+
+// serveContent writes the content named by name, downloadName is sent by the Content-Disposition header.
+// Range and conditional requests are served by http.ServeContent if the content is seekable,
+// otherwise only If-Modified-Since is supported.
+func serveContent(ctx *gin.Context, res Response, content io.Reader, name, downloadName string, size int64, modTime time.Time) error {
+	contentType := res.ContentType()
+	if contentType == "" {
+		var err error
+		if contentType, content, err = detectContentType(name, content); err != nil {
+			return err
+		}
+	}
+
+	ctx.Header("Content-Type", contentType)
+	if downloadName != "" {
+		ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": downloadName}))
+	}
+
+	if seeker, isSeeker := content.(io.ReadSeeker); isSeeker {
+		http.ServeContent(ctx.Writer, ctx.Request, name, modTime, seeker)
+		return nil
+	}
+
+	if !modTime.IsZero() {
+		ctx.Header("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+		if isNotModified(ctx.Request, modTime) {
+			ctx.Status(http.StatusNotModified)
+			return nil
+		}
+	}
+	if size > 0 {
+		ctx.Header("Content-Length", strconv.FormatInt(size, 10))
+	}
+
+	ctx.Status(res.Status())
+	if ctx.Request.Method == "HEAD" {
+		return nil
+	}
+	if _, err := io.Copy(ctx.Writer, content); err != nil {
+		_ = ctx.Error(err)
+	}
+	return nil
+}
+
+// detectContentType detects the content type by the name extension or the leading bytes of the content.
+// The returned reader yields the whole content, it is the given reader if the content is seekable.
+func detectContentType(name string, content io.Reader) (string, io.Reader, error) {
+	if contentType := mime.TypeByExtension(filepath.Ext(name)); contentType != "" {
+		return contentType, content, nil
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	contentType := mimetype.Detect(head[:n]).String()
+
+	if seeker, isSeeker := content.(io.Seeker); isSeeker {
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return "", nil, err
+		}
+		return contentType, content, nil
+	}
+	return contentType, io.MultiReader(bytes.NewReader(head[:n]), content), nil
+}
+
+func isNotModified(r *http.Request, modTime time.Time) bool {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	return err == nil && !modTime.Truncate(time.Second).After(since)
+}
+
+// fileError responds absent files with 404 Not Found.
+func fileError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		return NewHTTPError(http.StatusNotFound, "file not found").WithCode("file_not_found").Wrap(err)
+	}
+	return err
+}
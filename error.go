@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx/requestbody"
+	"github.com/paveldanilin/ginx/resolver"
+	"net/http"
 )
 
 type ErrorInterceptor interface {
@@ -28,12 +31,78 @@ type errorEvent struct {
 	res Response
 }
 
-func newError(ctx *gin.Context, err any) *errorEvent {
+// statusCoder is implemented by errors which define the HTTP response status, i.e. HTTPError.
+type statusCoder interface {
+	StatusCode() int
+}
+
+// bodyProvider is implemented by errors which define the HTTP response body, i.e. ValidationError.
+type bodyProvider interface {
+	ResponseBody() any
+}
+
+// newError creates an error event, the response status is defined by the error itself (see HTTPError)
+// or by the controller error mappings (see ErrorStatus), otherwise it is 500 Internal Server Error.
+func (c *Controller) newError(ctx *gin.Context, err any) *errorEvent {
+	e := anyToError(err)
+
+	var bindingErr *resolver.BindingError
+	if errors.As(e, &bindingErr) {
+		e = bindingHTTPError(bindingErr)
+	}
+
+	var missingErr *resolver.MissingError
+	if errors.As(e, &missingErr) {
+		e = missingHTTPError(missingErr)
+	}
+
+	var fileLimitErr *resolver.FileLimitError
+	if errors.As(e, &fileLimitErr) {
+		e = fileLimitHTTPError(fileLimitErr)
+	}
+
+	var mediaTypeErr *requestbody.UnsupportedMediaTypeError
+	if errors.As(e, &mediaTypeErr) {
+		e = unsupportedMediaTypeHTTPError(mediaTypeErr)
+	}
+
+	res := NewResponse(http.StatusInternalServerError)
+
+	var sc statusCoder
+	if errors.As(e, &sc) {
+		res.SetStatus(sc.StatusCode())
+	} else if m, mapped := c.findErrorMapping(e); mapped {
+		res.SetStatus(m.status)
+		// The error may carry internal details, so only the mapped message is exposed.
+		res.SetBody(NewHTTPError(m.status, m.message).ResponseBody())
+	}
+
+	var bp bodyProvider
+	if errors.As(e, &bp) {
+		res.SetBody(bp.ResponseBody())
+	}
+
+	if p := c.getProblemDetails(); p != nil {
+		res.SetBody(p.toProblem(ctx, res))
+		res.SetContentType(MIMEProblemJSON)
+	}
+
 	return &errorEvent{
 		ctx: ctx,
-		err: anyToError(err),
-		res: NewResponse(500),
+		err: e,
+		res: res,
+	}
+}
+
+func (c *Controller) findErrorMapping(err error) (errorMapping, bool) {
+	for owner := c; owner != nil; owner = owner.parent {
+		for _, m := range owner.errorMappings {
+			if m.match(err) {
+				return m, true
+			}
+		}
 	}
+	return errorMapping{}, false
 }
 
 func (e errorEvent) Context() *gin.Context {
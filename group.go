@@ -0,0 +1,92 @@
+package ginx
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx/slices"
+	"path"
+	"strings"
+)
+
+// Group creates a child controller serving routes under the relative path.
+// The child inherits argument resolvers, services, middlewares, renderers, the error interceptor, error mappings
+// and ContentType from its parent, the given options are applied to the child only.
+// The validator is shared with the parent, so validations registered by the child are visible to the whole tree.
+//
+//	api := controller.Group("/api", authMiddleware)
+//	v1 := api.Group("/v1", resolver.Struct())
+//	v1.GET("/users", listUsers) // GET /api/v1/users
+func (c *Controller) Group(relativePath string, opts ...Option) *Controller {
+	child := &Controller{
+		router:            c.router.Group(c.BasePath + relativePath),
+		parent:            c,
+		handlerMap:        c.handlerMap,
+		argumentResolvers: []ArgumentResolver{},
+		middlewares:       []gin.HandlerFunc{},
+		tester:            c.tester,
+		renderers:         rendererMap{},
+		validator:         c.validator,
+	}
+
+	for _, opt := range opts {
+		child.Use(opt)
+	}
+
+	return child
+}
+
+// allArgumentResolvers returns argument resolvers of the controller and its ancestors,
+// exclusive resolvers of the ancestors are dropped if the controller replaces them.
+func (c *Controller) allArgumentResolvers() []ArgumentResolver {
+	if c.parent == nil {
+		return c.argumentResolvers
+	}
+	inherited := slices.Filter(c.parent.allArgumentResolvers(), func(_ int, inherited ArgumentResolver) bool {
+		_, isReplaced := slices.First(c.argumentResolvers, func(r ArgumentResolver) bool {
+			return replaces(r, inherited)
+		})
+		return !isReplaced
+	})
+	return slices.Join(inherited, c.argumentResolvers)
+}
+
+// allMiddlewares returns middlewares of the controller and its ancestors, the ancestor middlewares go first.
+func (c *Controller) allMiddlewares() []gin.HandlerFunc {
+	if c.parent == nil {
+		return c.middlewares
+	}
+	return slices.Join(c.parent.allMiddlewares(), c.middlewares)
+}
+
+func (c *Controller) getErrorInterceptor() ErrorInterceptor {
+	if c.errorInterceptor == nil && c.parent != nil {
+		return c.parent.getErrorInterceptor()
+	}
+	return c.errorInterceptor
+}
+
+func (c *Controller) getContentType() string {
+	if strings.TrimSpace(c.ContentType) == "" && c.parent != nil {
+		return c.parent.getContentType()
+	}
+	return c.ContentType
+}
+
+func (c *Controller) getProblemDetails() *problemDetails {
+	if c.problemDetails == nil && c.parent != nil {
+		return c.parent.getProblemDetails()
+	}
+	return c.problemDetails
+}
+
+// joinPaths joins the router group base path and the relative path preserving a trailing slash.
+func joinPaths(basePath, relativePath string) string {
+	if relativePath == "" {
+		return basePath
+	}
+
+	joined := path.Join(basePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(joined, "/") {
+		return joined + "/"
+	}
+	return joined
+}
@@ -1,11 +1,15 @@
 package ginx
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx/resolver"
 	"github.com/paveldanilin/ginx/slices"
+	"net/http"
 	"reflect"
 	"sort"
+	"time"
 )
 
 // ArgumentResolver represents a request handler argument resolver.
@@ -20,6 +24,39 @@ type ArgumentResolver interface {
 	Resolve(*gin.Context, reflect.Type) (reflect.Value, error)
 }
 
+// StaticArgumentResolver is implemented by resolvers which CanResolve does not depend on the request context.
+// Such resolvers are chosen once per argument position when a handler is registered.
+// Other resolvers are asked per request, before the static resolver ranked below them.
+// An argument which no static resolver can resolve is reported at registration, unless other resolvers are used.
+type StaticArgumentResolver interface {
+	ArgumentResolver
+
+	// Static reports whether CanResolve ignores the request context.
+	Static() bool
+}
+
+// argumentVerifier is implemented by resolvers which check at registration that they can bind the argument,
+// i.e. that a wrapped value is convertible from request values or that a default is convertible into the argument.
+type argumentVerifier interface {
+	VerifyArgument(reflect.Type) error
+}
+
+// exclusiveResolver is implemented by resolvers a controller uses a single instance of, i.e. resolver.Struct.
+type exclusiveResolver interface {
+	Exclusive() bool
+}
+
+// replaces reports whether the resolver is exclusive and replaces the used resolver of the same type.
+func replaces(r, used ArgumentResolver) bool {
+	exclusive, isExclusive := r.(exclusiveResolver)
+	return isExclusive && exclusive.Exclusive() && reflect.TypeOf(r) == reflect.TypeOf(used)
+}
+
+// bodyLimiter is implemented by resolvers which bound the request body size, i.e. resolver.File.
+type bodyLimiter interface {
+	BodyLimit(reflect.Type) int64
+}
+
 // HandlerFunc represents a request handler.
 type HandlerFunc any
 
@@ -43,6 +80,19 @@ func ProduceXML() func(*handler) {
 	}
 }
 
+// Produces declares the list of content types the handler is able to produce.
+// The response content type is negotiated by the request Accept header,
+// the first content type is used if the client does not express any preference
+// and 406 Not Acceptable is responded if none of them is acceptable.
+// Handlers without Produces respond with their content type whatever the client accepts.
+//
+//	controller.GET("/users", loadUsers, ginx.Produces(gin.MIMEJSON, gin.MIMEXML))
+func Produces(contentTypes ...string) func(*handler) {
+	return func(h *handler) {
+		h.produces = contentTypes
+	}
+}
+
 type handlerOptions []HandlerOption
 
 func (o handlerOptions) Middlewares() []gin.HandlerFunc {
@@ -58,13 +108,38 @@ func (o handlerOptions) Middlewares() []gin.HandlerFunc {
 
 // handler represents a request handler definition.
 type handler struct {
+	// controller is the controller or the group the handler is registered on.
+	controller          *Controller
 	name                string
+	method              string
+	path                string
+	relativePath        string
 	responseContentType string
+	produces            []string
 	numIn               int
 	numOut              int
 	function            reflect.Value
 	arguments           []reflect.Type
 	resolvers           []ArgumentResolver
+	// plan holds the static argument resolver chosen at registration for each argument position, nil if there is none.
+	plan []ArgumentResolver
+	// candidates holds request dependent resolvers ranked above the planned one for each argument position,
+	// they are asked per request before the planned resolver.
+	candidates [][]ArgumentResolver
+	// planned reports whether resolvers of all arguments are chosen at registration.
+	planned bool
+	// bodyLimit bounds the request body size before arguments are resolved, zero if it is unbounded.
+	bodyLimit int64
+	// heartbeat is the interval of event stream heartbeats, nil for the default.
+	heartbeat *time.Duration
+	// invoke calls a typed handler without reflection, nil for handlers called by reflect.Value.Call.
+	invoke func(*gin.Context) (Response, error)
+	// servesFile marks handlers returning files, see File, Attachment and FSFile.
+	servesFile bool
+	// websocket marks handlers serving WebSocket connections, see Controller.WS.
+	websocket bool
+	// checkOrigin accepts the WebSocket handshake by the request origin, nil for the same origin check.
+	checkOrigin func(*http.Request) bool
 }
 
 func (h *handler) init(controllerArgumentResolvers []ArgumentResolver, opts ...HandlerOption) {
@@ -84,10 +159,120 @@ func (h *handler) init(controllerArgumentResolvers []ArgumentResolver, opts ...H
 		}
 	}
 
-	// Sort resolvers by priority
-	sort.Slice(h.resolvers, func(i, j int) bool {
+	// Event streams are served as text/event-stream unless the handler declares otherwise.
+	if h.responseContentType == "" && len(h.produces) == 0 && h.numOut > 0 && isEventStreamType(h.function.Type().Out(0)) {
+		h.responseContentType = MIMEEventStream
+	}
+
+	h.servesFile = h.numOut > 0 && isFileType(h.function.Type().Out(0))
+
+	// Sort resolvers by priority, the registration order breaks ties
+	sort.SliceStable(h.resolvers, func(i, j int) bool {
 		return h.resolvers[i].Priority() > h.resolvers[j].Priority()
 	})
+
+	// Compile the argument resolution plan
+	h.plan = make([]ArgumentResolver, h.numIn)
+	h.candidates = make([][]ArgumentResolver, h.numIn)
+	h.planned = true
+	for i, argumentType := range h.arguments {
+		h.plan[i], h.candidates[i] = h.planArgumentResolver(argumentType, i+1)
+		h.planned = h.planned && h.plan[i] != nil && len(h.candidates[i]) == 0
+	}
+	h.bodyLimit = h.planBodyLimit()
+}
+
+// planBodyLimit sums the body limits of the planned resolvers, the body is unbounded if any of them is unbounded.
+func (h *handler) planBodyLimit() int64 {
+	var limit int64
+	for i, r := range h.plan {
+		limiter, isLimiter := r.(bodyLimiter)
+		if !isLimiter {
+			continue
+		}
+		l := limiter.BodyLimit(h.arguments[i])
+		if l <= 0 {
+			return 0
+		}
+		limit += l
+	}
+	return limit
+}
+
+// planArgumentResolver returns the first static resolver which can resolve the argument without a request
+// and request dependent resolvers ranked above it.
+func (h *handler) planArgumentResolver(argumentType reflect.Type, argumentPosition int) (ArgumentResolver, []ArgumentResolver) {
+	var candidates []ArgumentResolver
+	for _, r := range h.resolvers {
+		if static, isStatic := r.(StaticArgumentResolver); !isStatic || !static.Static() {
+			candidates = append(candidates, r)
+			continue
+		}
+		if r.CanResolve(nil, argumentType, argumentPosition) {
+			return r, candidates
+		}
+	}
+	return nil, candidates
+}
+
+// argumentResolver returns the resolver of the argument at the index, request dependent candidates are asked first.
+func (h *handler) argumentResolver(ctx *gin.Context, argumentIndex int) ArgumentResolver {
+	for _, r := range h.candidates[argumentIndex] {
+		if r.CanResolve(ctx, h.arguments[argumentIndex], argumentIndex+1) {
+			return r
+		}
+	}
+	return h.plan[argumentIndex]
+}
+
+// verify checks that every argument has a resolver and the handler returns one of the supported shapes:
+// (), (<value>), (<error>), (<value>, <error>), (<error>, <value>), (<value>, <int|uint status>).
+func (h *handler) verify() error {
+	for i, argumentType := range h.arguments {
+		// Request dependent resolvers may resolve the argument, so it is checked per request.
+		if h.plan[i] == nil && len(h.candidates[i]) == 0 {
+			return fmt.Errorf("handler %s: resolver not found for argument at position [%d] of type %s", h.name, i+1, argumentType)
+		}
+		if v, isVerifier := h.plan[i].(argumentVerifier); isVerifier {
+			if err := v.VerifyArgument(argumentType); err != nil {
+				return fmt.Errorf("handler %s: argument at position [%d] of type %s: %w", h.name, i+1, argumentType, err)
+			}
+		}
+	}
+
+	if h.websocket {
+		return h.verifyWebSocket()
+	}
+
+	out := h.function.Type()
+	switch h.numOut {
+	case 0, 1:
+		return nil
+	case 2:
+		if out.Out(0).Implements(errType) || out.Out(1).Implements(errType) || isStatusType(out.Out(1)) {
+			return nil
+		}
+		return fmt.Errorf("handler %s: unsupported return types (%s, %s), expected (<value>, error) or (<value>, <status>)", h.name, out.Out(0), out.Out(1))
+	}
+
+	return fmt.Errorf("handler %s: too many return values [%d], expected at most 2", h.name, h.numOut)
+}
+
+// verifyWebSocket checks that the WebSocket handler takes *Conn and returns either nothing or an error.
+func (h *handler) verifyWebSocket() error {
+	_, takesConn := slices.First(h.arguments, func(t reflect.Type) bool {
+		return t == connType
+	})
+	if !takesConn {
+		return fmt.Errorf("handler %s: websocket handler must take %s argument", h.name, connType)
+	}
+
+	out := h.function.Type()
+	if h.numOut > 1 || h.numOut == 1 && out.Out(0) != errType {
+		return fmt.Errorf("handler %s: unsupported return types of websocket handler, expected () or (error)", h.name)
+	}
+
+	return nil
 }
 
 func (h *handler) findArgumentResolver(ctx *gin.Context, argumentType reflect.Type, argumentIndex int) ArgumentResolver {
@@ -100,26 +285,57 @@ func (h *handler) findArgumentResolver(ctx *gin.Context, argumentType reflect.Ty
 	return nil
 }
 
-func (h *handler) resolveArguments(ctx *gin.Context) ([]reflect.Value, error) {
-	var args []reflect.Value
+// resolveArguments resolves the handler arguments.
+// Absent required variables of all arguments are reported together by a single resolver.MissingError.
+func (h *handler) resolveArguments(ctx *gin.Context) ([]reflect.Value, []ArgumentResolver, error) {
+	// The body is bounded before any resolver reads it, i.e. a form value parsing the multipart body.
+	if h.bodyLimit > 0 {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.bodyLimit)
+	}
+
+	args := make([]reflect.Value, h.numIn)
+	// The plan is shared by requests, so it is returned as is only if no resolver is looked up per request.
+	resolvers := h.plan
+	if !h.planned {
+		resolvers = make([]ArgumentResolver, h.numIn)
+	}
+	var missing []resolver.Variable
 
 	for i, at := range h.arguments {
 		argumentPosition := i + 1
 		argumentType := at
 
-		// TODO: can we cache this?
-		resolver := h.findArgumentResolver(ctx, argumentType, argumentPosition)
-		if resolver == nil {
-			return nil, fmt.Errorf("resolver not found for argument at position [%d]", argumentPosition)
+		argumentResolver := h.argumentResolver(ctx, i)
+		if argumentResolver == nil {
+			return nil, nil, fmt.Errorf("resolver not found for argument at position [%d]", argumentPosition)
 		}
 
-		resolved, err := resolver.Resolve(ctx, argumentType)
+		resolved, err := argumentResolver.Resolve(ctx, argumentType)
 		if err != nil {
-			return nil, err
+			var missingErr *resolver.MissingError
+			if !errors.As(err, &missingErr) {
+				return nil, nil, err
+			}
+			missing = append(missing, missingErr.Variables...)
+			continue
+		}
+
+		args[i] = resolved
+		if !h.planned {
+			resolvers[i] = argumentResolver
 		}
+	}
 
-		args = append(args, resolved)
+	if len(missing) > 0 {
+		return nil, nil, &resolver.MissingError{Variables: missing}
 	}
 
-	return args, nil
+	return args, resolvers, nil
+}
+
+func (o handlerOptions) withoutResolvers() handlerOptions {
+	return slices.Filter(o, func(_ int, t HandlerOption) bool {
+		_, isResolver := t.(ArgumentResolver)
+		return !isResolver
+	})
 }
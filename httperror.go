@@ -0,0 +1,113 @@
+package ginx
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+)
+
+// HTTPError represents an error responded with the given HTTP status.
+//
+//	controller.GET("/users/:id", func(id int) (*User, error) {
+//		u, found := repo.Find(id)
+//		if !found {
+//			return nil, ginx.NewHTTPError(http.StatusNotFound, "user not found").WithCode("user_not_found")
+//		}
+//		return u, nil
+//	}, resolver.Path("id", 1))
+type HTTPError struct {
+	Status  int
+	Code    string
+	Message string
+	Details any
+	Err     error
+}
+
+// NewHTTPError creates an error with the status and the message, an empty message defaults to the status text.
+func NewHTTPError(status int, message string) *HTTPError {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return &HTTPError{Status: status, Message: message}
+}
+
+// WithCode sets a machine-readable error code.
+func (e *HTTPError) WithCode(code string) *HTTPError {
+	e.Code = code
+	return e
+}
+
+// WithDetails sets additional error details sent to the client.
+func (e *HTTPError) WithDetails(details any) *HTTPError {
+	e.Details = details
+	return e
+}
+
+// Wrap sets the underlying error which is not exposed to the client.
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	e.Err = err
+	return e
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the HTTP response status.
+func (e *HTTPError) StatusCode() int {
+	return e.Status
+}
+
+// ResponseBody returns the error representation sent to the client.
+func (e *HTTPError) ResponseBody() any {
+	return httpErrorBody{Code: e.Code, Message: e.Message, Details: e.Details}
+}
+
+type httpErrorBody struct {
+	XMLName xml.Name `json:"-" xml:"error"`
+	Code    string   `json:"code,omitempty" xml:"code,omitempty"`
+	Message string   `json:"message" xml:"message"`
+	Details any      `json:"details,omitempty" xml:"details,omitempty"`
+}
+
+// errorMapping maps errors matched by errors.Is/As to an HTTP response status.
+type errorMapping struct {
+	status  int
+	message string
+	match   func(error) bool
+}
+
+// WithMessage sets the message sent to the client, the status text by default.
+// The mapped error itself is not exposed to the client, it is available by Error.Error to error interceptors.
+//
+//	controller.Use(ginx.ErrorStatus(sql.ErrNoRows, http.StatusNotFound).WithMessage("record not found"))
+func (m errorMapping) WithMessage(message string) errorMapping {
+	m.message = message
+	return m
+}
+
+// ErrorStatus maps errors matching the target by errors.Is to the status.
+//
+//	controller.Use(ginx.ErrorStatus(sql.ErrNoRows, http.StatusNotFound))
+func ErrorStatus(target error, status int) errorMapping {
+	return errorMapping{status: status, match: func(err error) bool {
+		return errors.Is(err, target)
+	}}
+}
+
+// ErrorTypeStatus maps errors of the type T found by errors.As to the status.
+//
+//	controller.Use(ginx.ErrorTypeStatus[*ConflictError](http.StatusConflict))
+func ErrorTypeStatus[T error](status int) errorMapping {
+	return errorMapping{status: status, match: func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}}
+}
@@ -0,0 +1,126 @@
+package ginx
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrNotAcceptable is reported when none of the handler content types is acceptable by the client.
+var ErrNotAcceptable = NewHTTPError(http.StatusNotAcceptable, "not acceptable")
+
+// mediaRange represents a single entry of the Accept header, i.e.: "application/json;q=0.8".
+type mediaRange struct {
+	typ     string
+	subtype string
+	params  map[string]string
+	q       float64
+}
+
+// specificity ranks how precisely the range matches a media type, see RFC 7231 section 5.3.2.
+func (m mediaRange) specificity() int {
+	switch {
+	case m.typ == "*":
+		return 0
+	case m.subtype == "*":
+		return 1
+	}
+	return 2 + len(m.params)
+}
+
+func (m mediaRange) matches(typ, subtype string, params map[string]string) bool {
+	if m.typ != "*" && m.typ != typ {
+		return false
+	}
+	if m.subtype != "*" && m.subtype != subtype {
+		return false
+	}
+	for k, v := range m.params {
+		if params[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAccept parses the Accept header value into media ranges.
+// Malformed entries are skipped.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, entry := range strings.Split(accept, ",") {
+		mediaType, params := parseMediaType(entry)
+		typ, subtype, valid := strings.Cut(mediaType, "/")
+		if !valid || typ == "" || subtype == "" || (typ == "*" && subtype != "*") {
+			continue
+		}
+
+		r := mediaRange{typ: typ, subtype: subtype, params: map[string]string{}, q: 1}
+		for k, v := range params {
+			if k == "q" {
+				q, err := strconv.ParseFloat(v, 64)
+				if err != nil || q < 0 || q > 1 {
+					q = 0
+				}
+				r.q = q
+				continue
+			}
+			r.params[k] = v
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges
+}
+
+// parseMediaType splits "type/subtype; k=v" into a lower-cased media type and its parameters.
+func parseMediaType(s string) (string, map[string]string) {
+	parts := strings.Split(s, ";")
+	params := map[string]string{}
+
+	for _, p := range parts[1:] {
+		k, v, hasValue := strings.Cut(p, "=")
+		if !hasValue {
+			continue
+		}
+		params[strings.ToLower(strings.TrimSpace(k))] = strings.Trim(strings.TrimSpace(v), `"`)
+	}
+
+	return strings.ToLower(strings.TrimSpace(parts[0])), params
+}
+
+// negotiateContentType picks the offer preferred by the Accept header.
+// Offers are listed in the server preference order which is used to break ties.
+// An empty Accept header accepts the first offer.
+func negotiateContentType(accept string, offers []string) (string, bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	ranges := parseAccept(accept)
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		mediaType, params := parseMediaType(offer)
+		typ, subtype, _ := strings.Cut(mediaType, "/")
+
+		// The quality of an offer is defined by the most specific matching range.
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			if r.matches(typ, subtype, params) && r.specificity() > specificity {
+				q, specificity = r.q, r.specificity()
+			}
+		}
+
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best, bestQ > 0
+}
@@ -0,0 +1,351 @@
+package ginx
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/requestbody"
+	"github.com/paveldanilin/ginx/resolver"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var requestBodyType = reflect.TypeOf((*requestbody.RequestBody)(nil)).Elem()
+var fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+var multipartReaderType = reflect.TypeOf((*multipart.Reader)(nil))
+var anonymousFuncName = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
+var ginPathVariable = regexp.MustCompile(`[:*]([^/]+)`)
+
+// scopedResolver is implemented by resolvers binding a single request variable, i.e. resolver.Value.
+type scopedResolver interface {
+	Scope() resolver.Scope
+	Variable() string
+}
+
+// optionalResolver is implemented by resolvers of variables which may be required or have a default value.
+type optionalResolver interface {
+	IsRequired() bool
+	DefaultValue() any
+}
+
+// OpenAPI builds an OpenAPI 3.1 document describing all handlers registered on the controller.
+func (c *Controller) OpenAPI(info openapi.Info) *openapi.Document {
+	doc := openapi.New(info)
+	gen := openapi.NewGenerator()
+
+	handlerIds := make([]string, 0, len(c.handlerMap))
+	for id := range c.handlerMap {
+		handlerIds = append(handlerIds, id)
+	}
+	sort.Strings(handlerIds)
+
+	operationIds := map[string]bool{}
+	for _, id := range handlerIds {
+		h := c.handlerMap[id]
+		op := c.describeOperation(h, gen)
+		op.OperationID = uniqueOperationId(h, operationIds)
+		doc.Path(toOpenAPIPath(h.path)).SetOperation(h.method, op)
+	}
+
+	doc.Components = gen.Components()
+
+	return doc
+}
+
+// ServeOpenAPI registers an endpoint serving the controller OpenAPI document.
+// The document is rendered as JSON, or as YAML if requested by "?format=yaml" or by the Accept header.
+//
+//	controller.ServeOpenAPI("/openapi", openapi.Info{Title: "Blog API", Version: "1.0.0"})
+func (c *Controller) ServeOpenAPI(path string, info openapi.Info) {
+	c.router.GET(c.BasePath+normalizePath(path), func(ctx *gin.Context) {
+		doc := c.OpenAPI(info)
+
+		if ctx.Query("format") == "yaml" || strings.Contains(ctx.GetHeader("Accept"), "yaml") {
+			data, err := doc.YAML()
+			if err != nil {
+				_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+				return
+			}
+			ctx.Data(http.StatusOK, "application/yaml", data)
+			return
+		}
+
+		data, err := doc.JSON()
+		if err != nil {
+			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		ctx.Data(http.StatusOK, gin.MIMEJSON, data)
+	})
+}
+
+func (c *Controller) describeOperation(h *handler, gen *openapi.Generator) *openapi.Operation {
+	op := &openapi.Operation{
+		Responses: map[string]*openapi.Response{},
+	}
+
+	ctx := &gin.Context{Request: &http.Request{Method: h.method, Header: http.Header{}}}
+	declared := map[string]bool{}
+	form := &formBody{schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{}}}
+
+	for i, argumentType := range h.arguments {
+		r := h.findArgumentResolver(ctx, argumentType, i+1)
+		if r == nil {
+			continue
+		}
+
+		if _, isService := r.(*container); isService {
+			continue
+		}
+
+		if argumentType == multipartReaderType {
+			form.multipart = true
+			continue
+		}
+
+		if sr, isScoped := r.(scopedResolver); isScoped {
+			if sr.Scope() == resolver.ScopeForm {
+				form.add(sr.Variable(), argumentType, gen)
+				continue
+			}
+			schema, required := gen.Schema(argumentType), false
+			if opt, isOptional := r.(optionalResolver); isOptional {
+				schema.Default, required = opt.DefaultValue(), opt.IsRequired()
+			}
+			op.Parameters = appendParameter(op.Parameters, declared, string(sr.Scope()), sr.Variable(), required, schema)
+			continue
+		}
+
+		if binding, isParam := findParamBinding(argumentType); isParam {
+			op.Parameters = appendParameter(op.Parameters, declared, string(binding.scope), binding.variable, false, gen.Schema(binding.valueType))
+			continue
+		}
+
+		if argumentType.Kind() != reflect.Struct && argumentType.Kind() != reflect.Map {
+			continue
+		}
+
+		if argumentType.Kind() == reflect.Struct {
+			op.Parameters = describeTaggedFields(op.Parameters, declared, form, argumentType, gen)
+		}
+
+		if resolver.HttpMethodHasBody(h.method) && reflect.PointerTo(argumentType).Implements(requestBodyType) {
+			op.RequestBody = &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{}}
+			for _, mediaType := range requestBodyMediaTypes(argumentType) {
+				op.RequestBody.Content[mediaType] = openapi.MediaType{Schema: gen.Schema(argumentType)}
+			}
+		}
+	}
+
+	if op.RequestBody == nil && (form.multipart || len(form.schema.Properties) > 0) {
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+			form.mediaType(): {Schema: form.schema},
+		}}
+	}
+
+	// Every path variable must be declared, even if the handler does not bind it.
+	for _, m := range ginPathVariable.FindAllStringSubmatch(h.path, -1) {
+		op.Parameters = appendParameter(op.Parameters, declared, string(resolver.ScopePath), m[1], true, &openapi.Schema{Type: "string"})
+	}
+
+	if h.websocket {
+		op.Responses["101"] = &openapi.Response{Description: "Switching Protocols"}
+	} else {
+		op.Responses["200"] = c.describeResponse(h, gen)
+	}
+	for i := 0; i < h.numOut; i++ {
+		if h.function.Type().Out(i).Implements(errType) {
+			op.Responses["default"] = &openapi.Response{Description: "Error"}
+		}
+	}
+
+	return op
+}
+
+func (c *Controller) describeResponse(h *handler, gen *openapi.Generator) *openapi.Response {
+	res := &openapi.Response{Description: "OK"}
+
+	var bodyType reflect.Type
+	for i := 0; i < h.numOut; i++ {
+		outType := h.function.Type().Out(i)
+		if outType.Implements(errType) {
+			continue
+		}
+		bodyType = outType
+		break
+	}
+
+	if bodyType == nil || isStatusType(bodyType) {
+		return res
+	}
+
+	if isFileType(bodyType) {
+		res.Content = map[string]openapi.MediaType{
+			"application/octet-stream": {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+		}
+		return res
+	}
+
+	// Groups may have their own content type, so it is looked up on the controller the handler is registered on.
+	contentTypes := h.controller.producibleContentTypes(h)
+	if len(contentTypes) == 0 {
+		contentTypes = []string{gin.MIMEPlain}
+	}
+
+	schema := gen.Schema(bodyType)
+	switch {
+	case isStreamType(bodyType):
+		schema = &openapi.Schema{Type: "array", Items: gen.Schema(streamElementType(bodyType))}
+	case bodyType.Implements(readerType):
+		schema = &openapi.Schema{Type: "string", Format: "binary"}
+	}
+
+	res.Content = map[string]openapi.MediaType{}
+	for _, contentType := range contentTypes {
+		res.Content[contentType] = openapi.MediaType{Schema: schema}
+	}
+
+	return res
+}
+
+func describeTaggedFields(params []*openapi.Parameter, declared map[string]bool, form *formBody, t reflect.Type, gen *openapi.Generator) []*openapi.Parameter {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			params = describeTaggedFields(params, declared, form, field.Type, gen)
+		}
+
+		tagDef, hasTag := field.Tag.Lookup("ginx")
+		if !hasTag {
+			continue
+		}
+
+		entries := strings.Split(tagDef, ",")
+		options := map[string]string{}
+		for _, entry := range entries {
+			name, value, _ := strings.Cut(entry, "=")
+			options[name] = value
+		}
+		_, required := options["required"]
+
+		for _, entry := range entries {
+			scope, variable, isParam := strings.Cut(entry, "=")
+			if !isParam {
+				continue
+			}
+			switch resolver.Scope(scope) {
+			case resolver.ScopePath, resolver.ScopeQuery, resolver.ScopeHeader, resolver.ScopeCookie:
+				schema := gen.Schema(field.Type)
+				if defaultValue, hasDefault := options["default"]; hasDefault {
+					schema.Default = defaultValue
+				}
+				params = appendParameter(params, declared, scope, variable, required, schema)
+			case resolver.ScopeForm:
+				form.add(variable, field.Type, gen)
+			}
+		}
+	}
+	return params
+}
+
+func appendParameter(params []*openapi.Parameter, declared map[string]bool, in, name string, required bool, schema *openapi.Schema) []*openapi.Parameter {
+	key := in + ":" + name
+	if declared[key] {
+		return params
+	}
+	declared[key] = true
+
+	param := &openapi.Parameter{
+		Name:     name,
+		In:       in,
+		Required: required || in == string(resolver.ScopePath),
+		Schema:   schema,
+	}
+
+	// Maps are bound from query variables like filter[name]=x.
+	if in == string(resolver.ScopeQuery) && schema.Type == "object" {
+		param.Style = "deepObject"
+		param.Explode = true
+	}
+
+	return append(params, param)
+}
+
+func requestBodyMediaTypes(t reflect.Type) []string {
+	format := reflect.New(t).Interface().(requestbody.RequestBody).RequestBodyFormat()
+	return requestbody.MediaTypes(format)
+}
+
+// uniqueOperationId returns the operation id not taken yet by other operations of the document.
+// A function registered for several routes, or methods of the same name on different struct controllers,
+// would share the id, so the route is appended to the id taken already, i.e.: StatusText_get_b.
+func uniqueOperationId(h *handler, taken map[string]bool) string {
+	id := operationId(h)
+	if taken[id] {
+		id += "_" + routeOperationId(h)
+	}
+	for base, n := id, 2; taken[id]; n++ {
+		id = fmt.Sprintf("%s_%d", base, n)
+	}
+	taken[id] = true
+	return id
+}
+
+func operationId(h *handler) string {
+	if !anonymousFuncName.MatchString(h.name) {
+		if i := strings.LastIndex(h.name, "."); i >= 0 {
+			return h.name[i+1:]
+		}
+		return h.name
+	}
+
+	// Anonymous handlers are named after the route.
+	return routeOperationId(h)
+}
+
+// routeOperationId names the operation after the route, i.e.: GET /users/:id -> get_users_id.
+func routeOperationId(h *handler) string {
+	id := strings.ToLower(h.method)
+	for _, segment := range strings.Split(h.path, "/") {
+		segment = strings.TrimLeft(segment, ":*")
+		if segment != "" {
+			id += "_" + segment
+		}
+	}
+	return id
+}
+
+func toOpenAPIPath(path string) string {
+	return ginPathVariable.ReplaceAllString(path, "{$1}")
+}
+
+// formBody collects form fields bound by the handler into a request body schema.
+type formBody struct {
+	schema    *openapi.Schema
+	multipart bool
+}
+
+func (f *formBody) add(name string, t reflect.Type, gen *openapi.Generator) {
+	switch t {
+	case fileHeaderType:
+		f.schema.Properties[name] = &openapi.Schema{Type: "string", Format: "binary"}
+		f.multipart = true
+	case reflect.SliceOf(fileHeaderType):
+		f.schema.Properties[name] = &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string", Format: "binary"}}
+		f.multipart = true
+	default:
+		f.schema.Properties[name] = gen.Schema(t)
+	}
+}
+
+func (f *formBody) mediaType() string {
+	if f.multipart {
+		return gin.MIMEMultipartPOSTForm
+	}
+	return gin.MIMEPOSTForm
+}
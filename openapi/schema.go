@@ -0,0 +1,184 @@
+package openapi
+
+import (
+	"encoding"
+	"github.com/paveldanilin/ginx/resolver"
+	"path"
+	"reflect"
+	"strings"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// Schema represents a JSON Schema object.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Description          string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	Default              any                `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// Generator builds schemas from Go types, collecting named structs as reusable components.
+type Generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// NewGenerator creates a schema generator.
+func NewGenerator() *Generator {
+	return &Generator{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// Components returns the collected component schemas, or nil if there are none.
+func (g *Generator) Components() *Components {
+	if len(g.schemas) == 0 {
+		return nil
+	}
+	return &Components{Schemas: g.schemas}
+}
+
+// Schema returns a schema describing the given type.
+// Named struct types are registered as components and referenced by $ref.
+func (g *Generator) Schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == resolver.TimeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	// Types with a text representation, i.e. UUIDs or IP addresses.
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) && reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.Schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.Schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.component(t)}
+	}
+
+	// Interfaces, functions and channels are described as "any".
+	return &Schema{}
+}
+
+func (g *Generator) component(t reflect.Type) string {
+	if name, exists := g.names[t]; exists {
+		return name
+	}
+
+	name := componentName(t)
+	if _, taken := g.schemas[name]; taken {
+		name = componentName(t) + "_" + path.Base(t.PkgPath())
+	}
+
+	// Register the name before building the schema, so recursive types terminate.
+	g.names[t] = name
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.structSchema(t)
+
+	return name
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.collectFields(t, s)
+	if len(s.Properties) == 0 {
+		s.Properties = nil
+	}
+	return s
+}
+
+func (g *Generator) collectFields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, omitted := JSONFieldName(field)
+		if omitted {
+			continue
+		}
+
+		// Embedded structs without a json name have their fields promoted.
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && !hasJSONName(field) {
+			g.collectFields(fieldType, s)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		s.Properties[name] = g.Schema(field.Type)
+		if isRequiredField(field) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// JSONFieldName returns the name of the field in JSON documents, the second value reports whether the field is omitted.
+func JSONFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, false
+}
+
+func hasJSONName(field reflect.StructField) bool {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name != ""
+}
+
+func isRequiredField(field reflect.StructField) bool {
+	for _, key := range []string{"validate", "binding"} {
+		for _, rule := range strings.Split(field.Tag.Get(key), ",") {
+			if rule == "required" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func componentName(t reflect.Type) string {
+	return strings.NewReplacer("[", "_", "]", "", "/", "_", ".", "_", ",", "_", "*", "").Replace(t.Name())
+}
@@ -0,0 +1,141 @@
+package ginx
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx/resolver"
+	"reflect"
+)
+
+// Path binds a path variable.
+type Path[T any] struct {
+	Value T
+}
+
+func (Path[T]) paramScope() resolver.Scope {
+	return resolver.ScopePath
+}
+
+// Query binds a query string variable.
+type Query[T any] struct {
+	Value T
+}
+
+func (Query[T]) paramScope() resolver.Scope {
+	return resolver.ScopeQuery
+}
+
+// Header binds a request header.
+type Header[T any] struct {
+	Value T
+}
+
+func (Header[T]) paramScope() resolver.Scope {
+	return resolver.ScopeHeader
+}
+
+// param is implemented by Path, Query and Header.
+type param interface {
+	paramScope() resolver.Scope
+}
+
+var paramType = reflect.TypeOf((*param)(nil)).Elem()
+
+// paramBinding describes a struct argument embedding Path, Query or Header.
+type paramBinding struct {
+	fieldIndex int
+	scope      resolver.Scope
+	variable   string
+	valueType  reflect.Type
+}
+
+// valueResolver creates a resolver of the bound variable.
+func (b paramBinding) valueResolver() ArgumentResolver {
+	return resolver.Value(b.scope, b.variable, 0, nil)
+}
+
+func findParamBinding(t reflect.Type) (paramBinding, bool) {
+	if t.Kind() != reflect.Struct {
+		return paramBinding{}, false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.Anonymous || !field.Type.Implements(paramType) {
+			continue
+		}
+
+		variable := field.Tag.Get("ginx")
+		if variable == "" {
+			continue
+		}
+
+		return paramBinding{
+			fieldIndex: i,
+			scope:      reflect.Zero(field.Type).Interface().(param).paramScope(),
+			variable:   variable,
+			valueType:  field.Type.Field(0).Type,
+		}, true
+	}
+
+	return paramBinding{}, false
+}
+
+type paramResolver struct{}
+
+// Params creates a resolver which can inject self-describing arguments embedding Path, Query or Header.
+// A named struct embeds a wrapper and declares the variable name by the 'ginx' tag,
+// so the binding does not depend on the argument position:
+//
+//	type UserID struct {
+//		ginx.Path[int] `ginx:"id"`
+//	}
+//
+//	type Page struct {
+//		ginx.Query[int] `ginx:"page"`
+//	}
+//
+//	controller.GET("/users/:id/posts", func(page Page, id UserID) []Post {
+//		return repo.Posts(id.Value, page.Value)
+//	})
+func Params() *paramResolver {
+	return &paramResolver{}
+}
+
+func (r *paramResolver) Priority() int {
+	return 250
+}
+
+func (r *paramResolver) Static() bool {
+	return true
+}
+
+func (r *paramResolver) CanResolve(_ *gin.Context, argumentType reflect.Type, _ int) bool {
+	_, isParam := findParamBinding(argumentType)
+	return isParam
+}
+
+// VerifyArgument checks that the wrapped value is convertible from request values.
+func (r *paramResolver) VerifyArgument(argumentType reflect.Type) error {
+	binding, _ := findParamBinding(argumentType)
+
+	// The value resolver is not bound to an argument position, so it is asked for the position 0.
+	if !binding.valueResolver().CanResolve(nil, binding.valueType, 0) {
+		return fmt.Errorf("%s variable '%s' can not be bound to %s", binding.scope, binding.variable, binding.valueType)
+	}
+	return nil
+}
+
+func (r *paramResolver) Resolve(ctx *gin.Context, argumentType reflect.Type) (reflect.Value, error) {
+	binding, _ := findParamBinding(argumentType)
+
+	v, err := binding.valueResolver().Resolve(ctx, binding.valueType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	arg := reflect.New(argumentType).Elem()
+	arg.Field(binding.fieldIndex).Field(0).Set(v)
+
+	return arg, nil
+}
@@ -0,0 +1,118 @@
+package ginx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// MIMEProblemJSON is the RFC 9457 problem details media type.
+const MIMEProblemJSON = "application/problem+json"
+
+// Problem represents RFC 9457 problem details.
+// Extensions are serialized as top-level members next to the standard ones.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := map[string]any{}
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+
+	return json.Marshal(members)
+}
+
+type problemDetails struct {
+	typeURI     string
+	traceHeader string
+}
+
+// ProblemDetails enables rendering of errors as RFC 9457 problem details (application/problem+json).
+//
+//	controller.Use(ginx.ProblemDetails().TypeURI("https://example.com/problems/").TraceHeader("X-Trace-ID"))
+func ProblemDetails() *problemDetails {
+	return &problemDetails{traceHeader: "X-Request-ID"}
+}
+
+// TypeURI sets the base URI of problem types, the error code is appended to it.
+// Problems without an error code have the "about:blank" type.
+func (p *problemDetails) TypeURI(uri string) *problemDetails {
+	p.typeURI = uri
+	return p
+}
+
+// TraceHeader sets the request header carrying a trace identifier.
+// If the header is absent, the identifier is generated and sent by the response header of the same name.
+func (p *problemDetails) TraceHeader(header string) *problemDetails {
+	p.traceHeader = header
+	return p
+}
+
+// toProblem converts the error response into problem details.
+func (p *problemDetails) toProblem(ctx *gin.Context, res Response) *Problem {
+	problem := &Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(res.Status()),
+		Status:     res.Status(),
+		Instance:   ctx.Request.URL.Path,
+		Extensions: map[string]any{"traceId": p.traceId(ctx)},
+	}
+
+	switch body := res.Body().(type) {
+	case httpErrorBody:
+		problem.Detail = body.Message
+		if body.Code != "" {
+			problem.Extensions["code"] = body.Code
+			if p.typeURI != "" {
+				problem.Type = p.typeURI + body.Code
+			}
+		}
+		if body.Details != nil {
+			problem.Extensions["details"] = body.Details
+		}
+	case validationErrorBody:
+		problem.Detail = body.Message
+		problem.Extensions["errors"] = body.Errors
+	}
+
+	return problem
+}
+
+// traceId returns the trace identifier of the request.
+// A generated identifier is stored in the context by the "ginx_trace_id" key and sent by the trace header,
+// so the problem can be matched with the server logs.
+func (p *problemDetails) traceId(ctx *gin.Context) string {
+	if traceId := ctx.GetHeader(p.traceHeader); traceId != "" {
+		return traceId
+	}
+	if traceId := ctx.GetString("ginx_trace_id"); traceId != "" {
+		return traceId
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	traceId := hex.EncodeToString(b)
+
+	ctx.Set("ginx_trace_id", traceId)
+	ctx.Header(p.traceHeader, traceId)
+	return traceId
+}
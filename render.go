@@ -0,0 +1,79 @@
+package ginx
+
+import (
+	"github.com/paveldanilin/ginx/renderer"
+	"io"
+	"strings"
+)
+
+// Renderer represents a response body encoder.
+type Renderer interface {
+	// MediaTypes returns media types supported by the renderer, i.e.: "application/json".
+	MediaTypes() []string
+
+	// Render encodes the body into w.
+	Render(w io.Writer, body any) error
+}
+
+// defaultRenderers are used when a controller has no renderer registered for the media type.
+var defaultRenderers = rendererMap{}.add(
+	renderer.JSON(),
+	renderer.XML(),
+	renderer.YAML(),
+	renderer.TOML(),
+	renderer.MsgPack(),
+	renderer.ProtoBuf(),
+	renderer.CSV(),
+)
+
+type rendererMap map[string]Renderer
+
+func (m rendererMap) add(renderers ...Renderer) rendererMap {
+	for _, r := range renderers {
+		for _, mediaType := range r.MediaTypes() {
+			m[strings.ToLower(mediaType)] = r
+		}
+	}
+	return m
+}
+
+// find returns a renderer by the media type.
+// Structured syntax suffixes are resolved to the base format, i.e.: "application/vnd.api+json" -> "application/json".
+func (m rendererMap) find(contentType string) Renderer {
+	mediaType, _ := parseMediaType(contentType)
+
+	if r, exists := m[mediaType]; exists {
+		return r
+	}
+
+	if i := strings.LastIndex(mediaType, "+"); i >= 0 {
+		if r, exists := m["application/"+mediaType[i+1:]]; exists {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// withCharset appends the utf-8 charset to textual content types without an explicit charset.
+func withCharset(contentType string) string {
+	mediaType, params := parseMediaType(contentType)
+	if _, hasCharset := params["charset"]; hasCharset {
+		return contentType
+	}
+
+	if isTextMediaType(mediaType) {
+		return contentType + "; charset=utf-8"
+	}
+
+	return contentType
+}
+
+// isTextMediaType reports whether the media type is textual, i.e.: "text/csv" or "application/json".
+func isTextMediaType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") ||
+		strings.HasSuffix(mediaType, "yaml") ||
+		strings.HasSuffix(mediaType, "toml")
+}
@@ -0,0 +1,140 @@
+package renderer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+type csvRenderer struct {
+	mediaTypes
+}
+
+// CSV creates a renderer which encodes a response body as CSV.
+// The body must be [][]string, a struct or a slice of structs.
+// Struct fields are written in declaration order, a header is taken from the 'csv' tag or the field name.
+//
+//	type report struct {
+//		Name  string `csv:"name"`
+//		Total int    `csv:"total"`
+//		Notes string `csv:"-"`
+//	}
+func CSV() *csvRenderer {
+	return &csvRenderer{mediaTypes{values: []string{"text/csv"}}}
+}
+
+func (r *csvRenderer) Render(w io.Writer, body any) error {
+	cw := csv.NewWriter(w)
+
+	if rows, isRows := body.([][]string); isRows {
+		return r.flush(cw, cw.WriteAll(rows))
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(body))
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if err := cw.Write(r.header(v.Type())); err != nil {
+			return err
+		}
+		return r.flush(cw, cw.Write(r.record(v)))
+	case reflect.Slice, reflect.Array:
+		elemType := v.Type().Elem()
+		for elemType.Kind() == reflect.Pointer {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct {
+			return fmt.Errorf("csv: unsupported element type %s", elemType)
+		}
+
+		if err := cw.Write(r.header(elemType)); err != nil {
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := cw.Write(r.record(reflect.Indirect(v.Index(i)))); err != nil {
+				return err
+			}
+		}
+		return r.flush(cw, nil)
+	}
+
+	return fmt.Errorf("csv: unsupported body type %T", body)
+}
+
+func (r *csvRenderer) flush(cw *csv.Writer, err error) error {
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r *csvRenderer) header(t reflect.Type) []string {
+	var header []string
+	for i := 0; i < t.NumField(); i++ {
+		if name, include := csvFieldName(t.Field(i)); include {
+			header = append(header, name)
+		}
+	}
+	return header
+}
+
+func (r *csvRenderer) record(v reflect.Value) []string {
+	var record []string
+	for i := 0; i < v.NumField(); i++ {
+		if _, include := csvFieldName(v.Type().Field(i)); include {
+			record = append(record, fmt.Sprint(v.Field(i).Interface()))
+		}
+	}
+	return record
+}
+
+func csvFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name := field.Tag.Get("csv")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// CSVEncoder writes CSV rows one by one, i.e. for streamed responses.
+// Rows are []string or structs, the header is written before the first struct row.
+type CSVEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+// NewCSVEncoder creates an encoder writing to w.
+func NewCSVEncoder(w io.Writer) *CSVEncoder {
+	return &CSVEncoder{w: csv.NewWriter(w)}
+}
+
+// Encode writes the row and flushes it to the underlying writer.
+func (e *CSVEncoder) Encode(row any) error {
+	r := &csvRenderer{}
+
+	if record, isRecord := row.([]string); isRecord {
+		return r.flush(e.w, e.w.Write(record))
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(row))
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("csv: unsupported row type %T", row)
+	}
+
+	if !e.headerWritten {
+		if err := e.w.Write(r.header(v.Type())); err != nil {
+			return err
+		}
+		e.headerWritten = true
+	}
+
+	return r.flush(e.w, e.w.Write(r.record(v)))
+}
@@ -0,0 +1,24 @@
+package renderer
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type jsonRenderer struct {
+	mediaTypes
+}
+
+// JSON creates a renderer which encodes a response body as JSON.
+func JSON() *jsonRenderer {
+	return &jsonRenderer{mediaTypes{values: []string{"application/json"}}}
+}
+
+func (r *jsonRenderer) Render(w io.Writer, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
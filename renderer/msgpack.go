@@ -0,0 +1,20 @@
+package renderer
+
+import (
+	"github.com/ugorji/go/codec"
+	"io"
+)
+
+type msgPackRenderer struct {
+	mediaTypes
+}
+
+// MsgPack creates a renderer which encodes a response body as MessagePack.
+func MsgPack() *msgPackRenderer {
+	return &msgPackRenderer{mediaTypes{values: []string{"application/msgpack", "application/x-msgpack"}}}
+}
+
+func (r *msgPackRenderer) Render(w io.Writer, body any) error {
+	var mh codec.MsgpackHandle
+	return codec.NewEncoder(w, &mh).Encode(body)
+}
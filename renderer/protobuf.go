@@ -0,0 +1,31 @@
+package renderer
+
+import (
+	"fmt"
+	"google.golang.org/protobuf/proto"
+	"io"
+)
+
+type protoBufRenderer struct {
+	mediaTypes
+}
+
+// ProtoBuf creates a renderer which encodes a response body as Protocol Buffers.
+// The body must implement proto.Message.
+func ProtoBuf() *protoBufRenderer {
+	return &protoBufRenderer{mediaTypes{values: []string{"application/x-protobuf", "application/protobuf"}}}
+}
+
+func (r *protoBufRenderer) Render(w io.Writer, body any) error {
+	m, isMessage := body.(proto.Message)
+	if !isMessage {
+		return fmt.Errorf("protobuf: %T does not implement proto.Message", body)
+	}
+
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
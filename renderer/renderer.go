@@ -0,0 +1,9 @@
+package renderer
+
+type mediaTypes struct {
+	values []string
+}
+
+func (m mediaTypes) MediaTypes() []string {
+	return m.values
+}
@@ -0,0 +1,19 @@
+package renderer
+
+import (
+	"github.com/pelletier/go-toml/v2"
+	"io"
+)
+
+type tomlRenderer struct {
+	mediaTypes
+}
+
+// TOML creates a renderer which encodes a response body as TOML.
+func TOML() *tomlRenderer {
+	return &tomlRenderer{mediaTypes{values: []string{"application/toml"}}}
+}
+
+func (r *tomlRenderer) Render(w io.Writer, body any) error {
+	return toml.NewEncoder(w).Encode(body)
+}
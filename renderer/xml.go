@@ -0,0 +1,19 @@
+package renderer
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type xmlRenderer struct {
+	mediaTypes
+}
+
+// XML creates a renderer which encodes a response body as XML.
+func XML() *xmlRenderer {
+	return &xmlRenderer{mediaTypes{values: []string{"application/xml", "text/xml"}}}
+}
+
+func (r *xmlRenderer) Render(w io.Writer, body any) error {
+	return xml.NewEncoder(w).Encode(body)
+}
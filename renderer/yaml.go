@@ -0,0 +1,24 @@
+package renderer
+
+import (
+	"gopkg.in/yaml.v3"
+	"io"
+)
+
+type yamlRenderer struct {
+	mediaTypes
+}
+
+// YAML creates a renderer which encodes a response body as YAML.
+func YAML() *yamlRenderer {
+	return &yamlRenderer{mediaTypes{values: []string{"application/yaml", "application/x-yaml", "text/yaml"}}}
+}
+
+func (r *yamlRenderer) Render(w io.Writer, body any) error {
+	data, err := yaml.Marshal(body)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
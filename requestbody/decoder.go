@@ -0,0 +1,128 @@
+package requestbody
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin/binding"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// BodyDecoder represents a request body decoder.
+type BodyDecoder interface {
+	// Format returns a format name matched against RequestBody.RequestBodyFormat(), i.e.: "json".
+	Format() string
+
+	// MediaTypes returns media types matched against the request Content-Type, i.e.: "application/json".
+	MediaTypes() []string
+
+	// Decode decodes the body into v.
+	Decode(r io.Reader, v any) error
+}
+
+// UnsupportedMediaTypeError is returned when there is no decoder registered for the request body.
+type UnsupportedMediaTypeError struct {
+	Format      string
+	ContentType string
+}
+
+func (e *UnsupportedMediaTypeError) Error() string {
+	if e.Format != "" {
+		return fmt.Sprintf("unsupported body format '%s'", e.Format)
+	}
+	return fmt.Sprintf("unsupported media type '%s'", e.ContentType)
+}
+
+// StatusCode returns 415 Unsupported Media Type.
+func (e *UnsupportedMediaTypeError) StatusCode() int {
+	return http.StatusUnsupportedMediaType
+}
+
+var decodersMu sync.RWMutex
+var decoders []BodyDecoder
+
+func init() {
+	Register(bindingDecoder{format: "json", mediaTypes: []string{binding.MIMEJSON}, binding: binding.JSON})
+	Register(bindingDecoder{format: "xml", mediaTypes: []string{binding.MIMEXML, binding.MIMEXML2}, binding: binding.XML})
+	Register(bindingDecoder{format: "yaml", mediaTypes: []string{"application/yaml", binding.MIMEYAML, "text/yaml"}, binding: binding.YAML})
+	Register(bindingDecoder{format: "toml", mediaTypes: []string{binding.MIMETOML}, binding: binding.TOML})
+	Register(bindingDecoder{format: "msgpack", mediaTypes: []string{binding.MIMEMSGPACK2, binding.MIMEMSGPACK}, binding: binding.MsgPack})
+	Register(bindingDecoder{format: "protobuf", mediaTypes: []string{binding.MIMEPROTOBUF, "application/protobuf"}, binding: binding.ProtoBuf})
+	Register(formDecoder{})
+}
+
+// Register registers the decoder.
+// A decoder registered later takes precedence over decoders with the same format or media type.
+func Register(d BodyDecoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	decoders = append(decoders, d)
+}
+
+// Decoder returns a decoder by the format name.
+func Decoder(format string) (BodyDecoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	for i := len(decoders) - 1; i >= 0; i-- {
+		if decoders[i].Format() == format {
+			return decoders[i], true
+		}
+	}
+	return nil, false
+}
+
+// DecoderFor returns a decoder by the request Content-Type.
+// Structured syntax suffixes are resolved to the base format, i.e.: "application/vnd.api+json" -> "application/json".
+func DecoderFor(contentType string) (BodyDecoder, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	candidates := []string{mediaType}
+	if i := strings.LastIndex(mediaType, "+"); i >= 0 {
+		candidates = append(candidates, "application/"+mediaType[i+1:])
+	}
+
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	for _, candidate := range candidates {
+		for i := len(decoders) - 1; i >= 0; i-- {
+			for _, mt := range decoders[i].MediaTypes() {
+				if strings.EqualFold(mt, candidate) {
+					return decoders[i], true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// MediaTypes returns media types supported by the format.
+// All registered media types are returned for an empty format.
+func MediaTypes(format string) []string {
+	if format != "" {
+		if d, exists := Decoder(format); exists {
+			return d.MediaTypes()[:1]
+		}
+		return nil
+	}
+
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	var mediaTypes []string
+	seen := map[string]bool{}
+	for _, d := range decoders {
+		if !seen[d.Format()] {
+			seen[d.Format()] = true
+			mediaTypes = append(mediaTypes, d.MediaTypes()[0])
+		}
+	}
+	return mediaTypes
+}
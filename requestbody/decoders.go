@@ -0,0 +1,76 @@
+package requestbody
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"io"
+	"net/url"
+)
+
+// bindingDecoder decodes a body by the gin binding, which also validates 'binding' tags.
+type bindingDecoder struct {
+	format     string
+	mediaTypes []string
+	binding    binding.BindingBody
+}
+
+func (d bindingDecoder) Format() string {
+	return d.format
+}
+
+func (d bindingDecoder) MediaTypes() []string {
+	return d.mediaTypes
+}
+
+func (d bindingDecoder) Decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return d.binding.BindBody(data, v)
+}
+
+// formDecoder decodes an application/x-www-form-urlencoded body into a map or a struct tagged with 'form'.
+type formDecoder struct{}
+
+func (formDecoder) Format() string {
+	return "form"
+}
+
+func (formDecoder) MediaTypes() []string {
+	return []string{binding.MIMEPOSTForm}
+}
+
+func (formDecoder) Decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+
+	switch m := v.(type) {
+	case *FormData:
+		*m = FormData{}
+		for k := range values {
+			(*m)[k] = values.Get(k)
+		}
+		return nil
+	case *map[string]string:
+		*m = map[string]string{}
+		for k := range values {
+			(*m)[k] = values.Get(k)
+		}
+		return nil
+	case *map[string][]string:
+		*m = values
+		return nil
+	}
+
+	if err := binding.MapFormWithTag(v, values, "form"); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(v)
+}
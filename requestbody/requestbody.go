@@ -31,3 +31,73 @@ type XML struct{}
 func (x XML) RequestBodyFormat() string {
 	return "xml"
 }
+
+// YAML
+
+type YAMLData map[string]any
+
+func (y YAMLData) RequestBodyFormat() string {
+	return "yaml"
+}
+
+type YAML struct{}
+
+func (y YAML) RequestBodyFormat() string {
+	return "yaml"
+}
+
+// TOML
+
+type TOMLData map[string]any
+
+func (t TOMLData) RequestBodyFormat() string {
+	return "toml"
+}
+
+type TOML struct{}
+
+func (t TOML) RequestBodyFormat() string {
+	return "toml"
+}
+
+// MessagePack
+
+type MsgPackData map[string]any
+
+func (m MsgPackData) RequestBodyFormat() string {
+	return "msgpack"
+}
+
+type MsgPack struct{}
+
+func (m MsgPack) RequestBodyFormat() string {
+	return "msgpack"
+}
+
+// Form (application/x-www-form-urlencoded), struct fields are bound by the 'form' tag.
+
+type FormData map[string]string
+
+func (f FormData) RequestBodyFormat() string {
+	return "form"
+}
+
+type Form struct{}
+
+func (f Form) RequestBodyFormat() string {
+	return "form"
+}
+
+// Any decodes a body by the request Content-Type.
+
+type AnyData map[string]any
+
+func (a AnyData) RequestBodyFormat() string {
+	return ""
+}
+
+type Any struct{}
+
+func (a Any) RequestBodyFormat() string {
+	return ""
+}
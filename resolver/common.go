@@ -1,5 +1,11 @@
 package resolver
 
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
 type Scope string
 
 const (
@@ -11,8 +17,64 @@ const (
 
 	// ScopeHeader variable will be resolved by a request headers.
 	ScopeHeader Scope = "header"
+
+	// ScopeCookie variable will be resolved by a request cookie.
+	ScopeCookie Scope = "cookie"
+
+	// ScopeForm variable will be resolved by a request form, url-encoded or multipart.
+	ScopeForm Scope = "form"
+
+	// ScopeBody value will be resolved by a request body.
+	ScopeBody Scope = "body"
 )
 
+// BindingError is returned when a request value can not be bound to a handler argument,
+// i.e. a query string value "abc" to an int argument.
+type BindingError struct {
+	Scope    Scope
+	Variable string
+	Value    string
+	Type     reflect.Type
+	Err      error
+}
+
+func (e *BindingError) Error() string {
+	if e.Scope == ScopeBody {
+		return fmt.Sprintf("could not bind request body to %s: %v", e.Type, e.Err)
+	}
+	return fmt.Sprintf("could not bind %s variable '%s' value '%s' to %s: %v", e.Scope, e.Variable, e.Value, e.Type, e.Err)
+}
+
+func (e *BindingError) Unwrap() error {
+	return e.Err
+}
+
+// Variable identifies a request variable.
+type Variable struct {
+	Scope Scope
+	Name  string
+}
+
+// MissingError is returned when required request variables are absent.
+type MissingError struct {
+	Variables []Variable
+}
+
+func (e *MissingError) Error() string {
+	names := make([]string, 0, len(e.Variables))
+	for _, v := range e.Variables {
+		names = append(names, fmt.Sprintf("%s '%s'", v.Scope, v.Name))
+	}
+	return "missing required variables: " + strings.Join(names, ", ")
+}
+
+// static marks resolvers which CanResolve does not depend on the request context.
+type static struct{}
+
+func (static) Static() bool {
+	return true
+}
+
 type priority struct {
 	value int
 }
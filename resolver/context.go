@@ -10,11 +10,12 @@ var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
 
 type contextResolver struct {
 	priority
+	static
 }
 
 // Context creates a resolver which can inject gin.Context.Request.Context() into user handler argumentPosition.
 func Context() *contextResolver {
-	return &contextResolver{priority{value: 200}}
+	return &contextResolver{priority: priority{value: 200}}
 }
 
 func (r *contextResolver) CanResolve(_ *gin.Context, argumentType reflect.Type, _ int) bool {
@@ -0,0 +1,140 @@
+package resolver
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// TimeType is the type of time.Time values, which are converted from RFC 3339 strings rather than bound as structs.
+var TimeType = reflect.TypeOf(time.Time{})
+
+var converters = struct {
+	sync.RWMutex
+	m map[reflect.Type]func(string) (reflect.Value, error)
+}{m: map[reflect.Type]func(string) (reflect.Value, error){}}
+
+// RegisterConverter registers a function converting request values into values of the type T.
+// Registered converters take precedence over the built-in conversions.
+//
+//	resolver.RegisterConverter(func(s string) (uuid.UUID, error) {
+//		return uuid.Parse(s)
+//	})
+func RegisterConverter[T any](convert func(string) (T, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	converters.Lock()
+	defer converters.Unlock()
+
+	converters.m[t] = func(s string) (reflect.Value, error) {
+		v, err := convert(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(&v).Elem(), nil
+	}
+}
+
+func findConverter(t reflect.Type) (func(string) (reflect.Value, error), bool) {
+	converters.RLock()
+	defer converters.RUnlock()
+
+	convert, exists := converters.m[t]
+	return convert, exists
+}
+
+// isConvertible reports whether a request value can be converted into the type.
+func isConvertible(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if _, exists := findConverter(t); exists {
+		return true
+	}
+	return t == TimeType || isScalar(t) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// convertValue converts the request value into the type, the layout is used for time.Time values.
+// Pointers to convertible types are allocated, so a present but empty value is distinguishable from an absent one.
+func convertValue(val string, t reflect.Type, layout string) (reflect.Value, error) {
+	if t.Kind() == reflect.Pointer {
+		v, err := convertValue(val, t.Elem(), layout)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(v)
+		return p, nil
+	}
+
+	if convert, exists := findConverter(t); exists {
+		return convert(val)
+	}
+
+	switch t {
+	case TimeType:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		tm, err := time.Parse(layout, val)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(tm), nil
+	case durationType:
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(d), nil
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		p := reflect.New(t)
+		if err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val)); err != nil {
+			return reflect.Value{}, err
+		}
+		return p.Elem(), nil
+	}
+
+	// Scalars, including named types i.e.: type UserID int.
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(val, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(val, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetFloat(f)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+	}
+
+	return v, nil
+}
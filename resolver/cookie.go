@@ -0,0 +1,51 @@
+package resolver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidCookieSignature is returned by the signed cookie codec when a cookie value was tampered with.
+var ErrInvalidCookieSignature = errors.New("invalid cookie signature")
+
+// CookieCodec encodes and decodes cookie values, i.e. signs or encrypts them.
+type CookieCodec interface {
+	Encode(name, value string) (string, error)
+	Decode(name, value string) (string, error)
+}
+
+type signedCookieCodec struct {
+	key []byte
+}
+
+// SignedCookieCodec creates a codec which appends an HMAC-SHA256 signature to cookie values: <value>.<signature>.
+// The signature covers the cookie name, so a value can not be moved to another cookie.
+func SignedCookieCodec(key []byte) CookieCodec {
+	return &signedCookieCodec{key: key}
+}
+
+func (c *signedCookieCodec) Encode(name, value string) (string, error) {
+	return value + "." + c.sign(name, value), nil
+}
+
+func (c *signedCookieCodec) Decode(name, value string) (string, error) {
+	i := strings.LastIndexByte(value, '.')
+	if i < 0 {
+		return "", ErrInvalidCookieSignature
+	}
+
+	value, signature := value[:i], value[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(c.sign(name, value))) {
+		return "", ErrInvalidCookieSignature
+	}
+	return value, nil
+}
+
+func (c *signedCookieCodec) sign(name, value string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(name + "=" + value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,167 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+)
+
+var fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+var fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+var multipartReaderType = reflect.TypeOf((*multipart.Reader)(nil))
+
+// multipartOverhead is the allowance for part headers, boundaries and other form fields
+// when the request body size is bounded by the file limits.
+const multipartOverhead = 1 << 20
+
+// FileLimitError is returned when uploaded files exceed the limits of a file resolver.
+type FileLimitError struct {
+	Field string
+	Err   string
+}
+
+func (e *FileLimitError) Error() string {
+	return fmt.Sprintf("form file '%s': %s", e.Field, e.Err)
+}
+
+// StatusCode returns 413 Request Entity Too Large.
+func (e *FileLimitError) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+type fileResolver struct {
+	priority
+	static
+	field            string
+	argumentPosition int
+	maxFiles         int
+	maxSize          int64
+}
+
+// File creates a resolver which can inject an uploaded file of the multipart form field.
+// The argument type is *multipart.FileHeader or []*multipart.FileHeader for fields with several files,
+// nil is injected if the field has no files.
+//
+//	controller.POST("/albums/:id/photos", uploadPhotos, resolver.File("photos", 1).MaxFiles(10).MaxSize(5<<20))
+func File(field string, argumentPosition int) *fileResolver {
+	return &fileResolver{
+		priority:         priority{value: 250},
+		field:            field,
+		argumentPosition: argumentPosition,
+	}
+}
+
+// MaxFiles limits the number of files in the field.
+func (r *fileResolver) MaxFiles(n int) *fileResolver {
+	r.maxFiles = n
+	return r
+}
+
+// MaxSize limits the size of every file in bytes.
+// If the number of files is limited too, or the argument is a single file, the request body is bounded
+// by the files total size before it is read, so an oversized upload is rejected without being buffered.
+func (r *fileResolver) MaxSize(size int64) *fileResolver {
+	r.maxSize = size
+	return r
+}
+
+// Scope returns the scope the file is resolved from.
+func (r *fileResolver) Scope() Scope {
+	return ScopeForm
+}
+
+// Variable returns the name of the form field.
+func (r *fileResolver) Variable() string {
+	return r.field
+}
+
+func (r *fileResolver) CanResolve(_ *gin.Context, argumentType reflect.Type, argument int) bool {
+	return r.argumentPosition == argument && (argumentType == fileHeaderType || argumentType == fileHeadersType)
+}
+
+func (r *fileResolver) Resolve(ctx *gin.Context, argumentType reflect.Type) (reflect.Value, error) {
+	form, err := ctx.MultipartForm()
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return empty, &FileLimitError{Field: r.field, Err: fmt.Sprintf("request body is too large, expected at most %d bytes", maxBytesErr.Limit)}
+	}
+	if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return empty, &BindingError{Scope: ScopeForm, Variable: r.field, Type: argumentType, Err: err}
+	}
+
+	var files []*multipart.FileHeader
+	if form != nil {
+		files = form.File[r.field]
+	}
+
+	if r.maxFiles > 0 && len(files) > r.maxFiles {
+		return empty, &FileLimitError{Field: r.field, Err: fmt.Sprintf("too many files [%d], expected at most %d", len(files), r.maxFiles)}
+	}
+	if argumentType == fileHeaderType && len(files) > 1 {
+		return empty, &FileLimitError{Field: r.field, Err: fmt.Sprintf("too many files [%d], expected one", len(files))}
+	}
+	for _, f := range files {
+		if r.maxSize > 0 && f.Size > r.maxSize {
+			return empty, &FileLimitError{Field: r.field, Err: fmt.Sprintf("file '%s' is too large [%d bytes], expected at most %d bytes", f.Filename, f.Size, r.maxSize)}
+		}
+	}
+
+	if argumentType == fileHeadersType {
+		return reflect.ValueOf(files), nil
+	}
+	if len(files) == 0 {
+		return reflect.Zero(fileHeaderType), nil
+	}
+	return reflect.ValueOf(files[0]), nil
+}
+
+// BodyLimit returns the request body size bound derived from the file limits, zero if it is unbounded.
+// The handler bounds the request body before any argument is resolved, so form values parsed by other resolvers
+// are bounded too.
+func (r *fileResolver) BodyLimit(argumentType reflect.Type) int64 {
+	maxFiles := r.maxFiles
+	if argumentType == fileHeaderType {
+		maxFiles = 1
+	}
+	if r.maxSize <= 0 || maxFiles <= 0 {
+		return 0
+	}
+	return int64(maxFiles)*r.maxSize + multipartOverhead
+}
+
+type multipartReaderResolver struct {
+	priority
+	static
+	maxSize int64
+}
+
+// MultipartReader creates a resolver which can inject *multipart.Reader to stream a multipart request body
+// without buffering uploaded files.
+func MultipartReader() *multipartReaderResolver {
+	return &multipartReaderResolver{priority: priority{value: 200}}
+}
+
+// MaxSize limits the size of the request body in bytes, reading beyond the limit fails.
+func (r *multipartReaderResolver) MaxSize(size int64) *multipartReaderResolver {
+	r.maxSize = size
+	return r
+}
+
+func (r *multipartReaderResolver) CanResolve(_ *gin.Context, argumentType reflect.Type, _ int) bool {
+	return argumentType == multipartReaderType
+}
+
+func (r *multipartReaderResolver) Resolve(ctx *gin.Context, argumentType reflect.Type) (reflect.Value, error) {
+	if r.maxSize > 0 {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, r.maxSize)
+	}
+
+	reader, err := ctx.Request.MultipartReader()
+	if err != nil {
+		return empty, &BindingError{Scope: ScopeBody, Type: argumentType, Err: err}
+	}
+	return reflect.ValueOf(reader), nil
+}
@@ -9,11 +9,12 @@ var ginContextType = reflect.TypeOf((*gin.Context)(nil))
 
 type ginContextResolver struct {
 	priority
+	static
 }
 
 // GinContext creates a resolver which can inject *gin.Context into user handler argumentPosition.
 func GinContext() *ginContextResolver {
-	return &ginContextResolver{priority{value: 200}}
+	return &ginContextResolver{priority: priority{value: 200}}
 }
 
 func (r *ginContextResolver) CanResolve(_ *gin.Context, argumentType reflect.Type, _ int) bool {
@@ -10,6 +10,7 @@ var httpRequestType = reflect.TypeOf((*http.Request)(nil))
 
 type httpRequestResolver struct {
 	priority
+	static
 }
 
 // HttpRequest resolver injects *http.Request into user handler.
@@ -18,7 +19,7 @@ type httpRequestResolver struct {
 //		// Do something with http.Request
 //	})
 func HttpRequest() *httpRequestResolver {
-	return &httpRequestResolver{priority{value: 200}}
+	return &httpRequestResolver{priority: priority{value: 200}}
 }
 
 func (r *httpRequestResolver) CanResolve(_ *gin.Context, argumentType reflect.Type, _ int) bool {
@@ -5,8 +5,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/paveldanilin/ginx/requestbody"
 	"reflect"
-	"strconv"
 	"strings"
+	"sync"
 )
 
 const tagKey = "ginx"
@@ -21,6 +21,19 @@ type tagParam struct {
 
 type structResolver struct {
 	priority
+	static
+	cookieCodec CookieCodec
+	// fields caches bound fields by struct type, see structResolver.fields.
+	fields *sync.Map
+}
+
+// structField is a struct field bound to a request variable.
+type structField struct {
+	index    []int
+	variable string
+	// files marks multipart form fields bound to uploaded files.
+	files    bool
+	resolver *valueResolver
 }
 
 // Struct resolver injects a populated instance of the given struct.
@@ -43,7 +56,28 @@ type structResolver struct {
 //		return fmt.Sprintf("<%s:%d:%s>", o.Product, o.ID, o.Extra)
 //	})
 func Struct() *structResolver {
-	return &structResolver{priority{value: 200}}
+	return &structResolver{priority: priority{value: 200}, fields: &sync.Map{}}
+}
+
+// CookieCodec sets a codec decoding values of fields tagged by 'cookie', i.e. verifying a signature.
+//
+//	type session struct {
+//		ID string `ginx:"cookie=session"`
+//	}
+//
+//	controller.Use(resolver.Struct().CookieCodec(resolver.SignedCookieCodec(secret)))
+//
+// The resolver replaces the struct resolver used by the controller or inherited by the group.
+func (r *structResolver) CookieCodec(codec CookieCodec) *structResolver {
+	r.cookieCodec = codec
+	r.fields = &sync.Map{}
+	return r
+}
+
+// Exclusive reports that a controller uses a single struct resolver,
+// so the one configured by CookieCodec replaces the struct resolver of NewDefaultController.
+func (r *structResolver) Exclusive() bool {
+	return true
 }
 
 func (r structResolver) CanResolve(_ *gin.Context, argumentType reflect.Type, _ int) bool {
@@ -61,21 +95,102 @@ func (r structResolver) Resolve(ctx *gin.Context, argumentType reflect.Type) (re
 	}
 
 	if argumentType.Kind() == reflect.Struct {
-		r.processTag(ctx, indirect)
+		if err := r.bindFields(ctx, indirect); err != nil {
+			return empty, err
+		}
 	}
 
 	return indirect, nil
 }
 
-func (r structResolver) processTag(ctx *gin.Context, val reflect.Value) {
-	typ := val.Type()
+// VerifyArgument parses the fields of the struct argument once, a bad default of a field is reported at registration.
+func (r *structResolver) VerifyArgument(argumentType reflect.Type) error {
+	if argumentType.Kind() != reflect.Struct {
+		return nil
+	}
+	_, err := r.boundFields(argumentType)
+	return err
+}
+
+// bindFields binds fields tagged by 'ginx', absent required variables are reported together by MissingError.
+func (r structResolver) bindFields(ctx *gin.Context, val reflect.Value) error {
+	var missing []Variable
+
+	fields, err := r.boundFields(val.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		fieldValue := val.FieldByIndex(f.index)
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		if f.files {
+			if err := r.setFiles(ctx, fieldValue, f.variable); err != nil {
+				return err
+			}
+			continue
+		}
+
+		v, exists, err := f.resolver.resolve(ctx, fieldValue.Type())
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			if f.resolver.required {
+				missing = append(missing, Variable{Scope: f.resolver.scope, Name: f.resolver.variable})
+				continue
+			}
+			if f.resolver.defaultValue == nil {
+				continue
+			}
+			if v, err = f.resolver.defaultValueOf(fieldValue.Type()); err != nil {
+				return err
+			}
+		}
+
+		fieldValue.Set(v)
+	}
+
+	if len(missing) > 0 {
+		return &MissingError{Variables: missing}
+	}
+	return nil
+}
+
+// boundFields returns fields of the struct type bound to request variables, they are parsed once per type.
+func (r structResolver) boundFields(t reflect.Type) ([]structField, error) {
+	if fields, exists := r.fields.Load(t); exists {
+		return fields.([]structField), nil
+	}
+
+	fields, err := r.parseFields(t, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.fields.Store(t, fields)
+	return fields, nil
+}
+
+// parseFields parses 'ginx' tags of the struct type and its nested structs, index is the path to the struct.
+// Defaults of the fields are converted into the field types.
+func (r structResolver) parseFields(t reflect.Type, index []int) ([]structField, error) {
+	var fields []structField
 
-	for i := 0; i < val.NumField(); i++ {
-		field := typ.Field(i)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
 
 		// Nested
 		if field.Type.Kind() == reflect.Struct {
-			r.processTag(ctx, val.Field(i))
+			nested, err := r.parseFields(field.Type, fieldIndex)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
 		}
 
 		tagDef, hasTag := field.Tag.Lookup(tagKey)
@@ -83,26 +198,69 @@ func (r structResolver) processTag(ctx *gin.Context, val reflect.Value) {
 			continue
 		}
 
-		fieldValue := val.FieldByName(field.Name)
-		if !fieldValue.IsValid() || !fieldValue.CanSet() {
-			continue
-		}
-
 		tagParams := r.parseTag(tagDef)
 		for _, tParam := range tagParams {
-			switch tParam.name {
-			case string(ScopeQuery):
-				v := ctx.Query(tParam.value)
-				r.setField(fieldValue, v)
-			case string(ScopePath):
-				v := ctx.Param(tParam.value)
-				r.setField(fieldValue, v)
-			case string(ScopeHeader):
-				v := ctx.GetHeader(tParam.value)
-				r.setField(fieldValue, v)
+			scope := Scope(tParam.name)
+			switch scope {
+			case ScopePath, ScopeQuery, ScopeHeader, ScopeForm, ScopeCookie:
+			default:
+				continue
+			}
+
+			if scope == ScopeForm && (field.Type == fileHeaderType || field.Type == fileHeadersType) {
+				fields = append(fields, structField{index: fieldIndex, variable: tParam.value, files: true})
+				continue
+			}
+
+			vr := Value(scope, tParam.value, 0, nil).
+				Codec(r.cookieCodec).
+				Separator(tagParamValue(tagParams, "sep", ",")).
+				Layout(tagParamValue(tagParams, "layout", ""))
+			if !vr.isBindable(field.Type) {
+				continue
+			}
+			if _, required := findTagParam(tagParams, "required"); required {
+				vr.Required()
 			}
+			if defaultValue, hasDefault := findTagParam(tagParams, "default"); hasDefault {
+				if err := vr.Default(defaultValue).VerifyArgument(field.Type); err != nil {
+					return nil, fmt.Errorf("field %s: %w", field.Name, err)
+				}
+			}
+
+			fields = append(fields, structField{index: fieldIndex, variable: tParam.value, resolver: vr})
 		}
 	}
+
+	return fields, nil
+}
+
+// tagParamValue returns a value of the tag parameter, i.e. `ginx:"query=ids,sep=|"` or `ginx:"query=since,layout=2006-01-02"`.
+func tagParamValue(tagParams []tagParam, name, defaultValue string) string {
+	if value, exists := findTagParam(tagParams, name); exists {
+		return value
+	}
+	return defaultValue
+}
+
+// findTagParam looks up the tag parameter, flags like `ginx:"query=page,required"` have an empty value.
+func findTagParam(tagParams []tagParam, name string) (string, bool) {
+	for _, p := range tagParams {
+		if p.name == name {
+			return p.value, true
+		}
+	}
+	return "", false
+}
+
+// setFiles sets uploaded files of the multipart form field.
+func (r structResolver) setFiles(ctx *gin.Context, v reflect.Value, field string) error {
+	files, err := File(field, 0).Resolve(ctx, v.Type())
+	if err != nil {
+		return err
+	}
+	v.Set(files)
+	return nil
 }
 
 func (r structResolver) parseTag(tagDef string) []tagParam {
@@ -111,92 +269,43 @@ func (r structResolver) parseTag(tagDef string) []tagParam {
 	var params []tagParam
 
 	for i := 0; i < len(tagEntries); i++ {
-		kv := strings.SplitN(tagEntries[i], "=", 2)
-		if len(kv) != 2 {
+		name, value, _ := strings.Cut(tagEntries[i], "=")
+		if name == "" {
 			continue
 		}
-		params = append(params, tagParam{name: kv[0], value: kv[1]})
+		params = append(params, tagParam{name: name, value: value})
 	}
 
 	return params
 }
 
-func (r structResolver) setField(v reflect.Value, s string) {
-	switch v.Kind() {
-	case reflect.String:
-		v.SetString(s)
-	case reflect.Bool:
-		if s == "" {
-			v.SetBool(false)
-		} else {
-			b, _ := strconv.ParseBool(s)
-			v.SetBool(b)
-		}
-	case reflect.Int, reflect.Int32, reflect.Int64:
-		if s == "" {
-			v.SetInt(0)
-		} else {
-			i, _ := strconv.Atoi(s)
-			v.SetInt(int64(i))
-		}
-	case reflect.Float32:
-		if s == "" {
-			v.SetFloat(0)
-		} else {
-			f, _ := strconv.ParseFloat(s, 32)
-			v.SetFloat(f)
-		}
-	case reflect.Float64:
-		if s == "" {
-			v.SetFloat(0)
-		} else {
-			f, _ := strconv.ParseFloat(s, 64)
-			v.SetFloat(f)
-		}
-	}
-}
-
 func (r structResolver) bindBody(ctx *gin.Context, out reflect.Value) error {
-	if !httpMethodHasBody(ctx.Request.Method) || !out.Type().Implements(requestBodyType) {
+	if !HttpMethodHasBody(ctx.Request.Method) || !out.Type().Implements(requestBodyType) {
 		return nil
 	}
 
 	bodyFormat := out.Elem().MethodByName("RequestBodyFormat").Call([]reflect.Value{})[0].String()
 
+	var decoder requestbody.BodyDecoder
+	var decoderExists bool
 	if bodyFormat == "" {
-		bodyFormat = r.getFormat(ctx)
-	}
-
-	switch bodyFormat {
-	case "json":
-		err := ctx.BindJSON(out.Interface())
-		if err != nil {
-			return err
-		}
-		return nil
-	case "xml":
-		err := ctx.BindXML(out.Interface())
-		if err != nil {
-			return err
-		}
-		return nil
+		decoder, decoderExists = requestbody.DecoderFor(ctx.ContentType())
+	} else {
+		decoder, decoderExists = requestbody.Decoder(bodyFormat)
 	}
 
-	return fmt.Errorf("unnown body format '%s'", bodyFormat)
-}
-
-func (r structResolver) getFormat(ctx *gin.Context) string {
-	if strings.Contains(ctx.ContentType(), "json") {
-		return "json"
+	if !decoderExists {
+		return &requestbody.UnsupportedMediaTypeError{Format: bodyFormat, ContentType: ctx.ContentType()}
 	}
 
-	if strings.Contains(ctx.ContentType(), "xml") {
-		return "xml"
+	if err := decoder.Decode(ctx.Request.Body, out.Interface()); err != nil {
+		return &BindingError{Scope: ScopeBody, Type: out.Type().Elem(), Err: err}
 	}
 
-	return ctx.ContentType()
+	return nil
 }
 
-func httpMethodHasBody(method string) bool {
+// HttpMethodHasBody reports whether requests of the method carry a body which is bound to handler arguments.
+func HttpMethodHasBody(method string) bool {
 	return method == "POST" || method == "PUT" || method == "PATCH"
 }
@@ -1,19 +1,30 @@
 package resolver
 
 import (
-	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/textproto"
 	"reflect"
-	"strconv"
+	"strings"
+	"sync"
 )
 
 type valueResolver struct {
 	priority
+	static
 	scope            Scope
 	variable         string
 	argumentPosition int
 	defaultValue     any
+	validationRule   string
+	cookieCodec      CookieCodec
+	separator        string
+	layout           string
+	required         bool
+	// argumentType binds the resolver to arguments of the type instead of the argument position.
+	argumentType reflect.Type
+	// defaults caches the default value converted at registration by argument type, see VerifyArgument.
+	defaults *sync.Map
 }
 
 func Value(scope Scope, variable string, argumentPosition int, defaultValue any) *valueResolver {
@@ -23,6 +34,8 @@ func Value(scope Scope, variable string, argumentPosition int, defaultValue any)
 		variable:         variable,
 		argumentPosition: argumentPosition,
 		defaultValue:     defaultValue,
+		separator:        ",",
+		defaults:         &sync.Map{},
 	}
 }
 
@@ -45,136 +58,403 @@ func Header(headerVariable string, argumentPosition int) *valueResolver {
 	return Value(ScopeHeader, headerVariable, argumentPosition, nil)
 }
 
+// Form creates a resolver which can inject a form value.
+func Form(formField string, argumentPosition int) *valueResolver {
+	return Value(ScopeForm, formField, argumentPosition, nil)
+}
+
+// Cookie creates a resolver which can inject a cookie value.
+func Cookie(cookieName string, argumentPosition int) *valueResolver {
+	return Value(ScopeCookie, cookieName, argumentPosition, nil)
+}
+
+// PathFor creates a resolver which can inject a path value into arguments of the type T regardless of their position.
+// It is meant to be used with dedicated named types, so bindings survive reordering of handler arguments.
+//
+//	type UserName string
+//
+//	controller.GET("/users/:name", func(ctx context.Context, name UserName) User {
+//		...
+//	}, resolver.PathFor[UserName]("name"))
+func PathFor[T any](pathVariable string) *valueResolver {
+	return Value(ScopePath, pathVariable, 0, nil).forType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// QueryFor creates a resolver which can inject a query value into arguments of the type T regardless of their position.
+func QueryFor[T any](queryVariable string) *valueResolver {
+	return Value(ScopeQuery, queryVariable, 0, nil).forType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// HeaderFor creates a resolver which can inject a header value into arguments of the type T regardless of their position.
+func HeaderFor[T any](headerVariable string) *valueResolver {
+	return Value(ScopeHeader, headerVariable, 0, nil).forType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func (r *valueResolver) forType(argumentType reflect.Type) *valueResolver {
+	r.argumentType = argumentType
+	return r
+}
+
+// Codec sets a codec decoding the cookie value, i.e. verifying a signature.
+//
+//	controller.GET("/profile", showProfile, resolver.Cookie("session", 1).Codec(resolver.SignedCookieCodec(secret)))
+func (r *valueResolver) Codec(codec CookieCodec) *valueResolver {
+	r.cookieCodec = codec
+	return r
+}
+
+// Separator sets a separator of values bound to a slice or array, the default is comma: ?ids=1,2,3.
+// Repeated variables are joined: ?ids=1,2&ids=3. An empty separator disables splitting.
+func (r *valueResolver) Separator(separator string) *valueResolver {
+	r.separator = separator
+	return r
+}
+
+// Required demands presence of the variable, a missing variable is reported as 400 Bad Request.
+//
+//	controller.GET("/posts", listPosts, resolver.Query("page", 1).Required())
+func (r *valueResolver) Required() *valueResolver {
+	r.required = true
+	return r
+}
+
+// IsRequired reports whether the variable must be present.
+func (r *valueResolver) IsRequired() bool {
+	return r.required
+}
+
+// Default sets a value used if the variable is absent, a string is converted into the argument type.
+// Numbers are converted only if the value is kept, i.e. 3.7 is not a default of int arguments.
+// The default is converted when the handler is registered, so a bad default fails the registration.
+//
+//	controller.GET("/posts", listPosts, resolver.Query("limit", 1).Default(20))
+func (r *valueResolver) Default(defaultValue any) *valueResolver {
+	r.defaultValue = defaultValue
+	return r
+}
+
+// DefaultValue returns the value used if the variable is absent.
+func (r *valueResolver) DefaultValue() any {
+	return r.defaultValue
+}
+
+// Layout sets a layout of time.Time values, the default is time.RFC3339.
+//
+//	controller.GET("/events", listEvents, resolver.Query("since", 1).Layout("2006-01-02"))
+func (r *valueResolver) Layout(layout string) *valueResolver {
+	r.layout = layout
+	return r
+}
+
+// Validate sets a validation rule for the resolved value, the rule syntax is the same as for 'validate' tags.
+//
+//	controller.GET("/posts", listPosts, resolver.Query("page", 1).Validate("min=1,max=100"))
+func (r *valueResolver) Validate(rule string) *valueResolver {
+	r.validationRule = rule
+	return r
+}
+
+// ValidationRule returns the validation rule for the resolved value.
+func (r *valueResolver) ValidationRule() string {
+	return r.validationRule
+}
+
+// Scope returns the scope the variable is resolved from.
+func (r *valueResolver) Scope() Scope {
+	return r.scope
+}
+
+// Variable returns the name of the resolved variable.
+func (r *valueResolver) Variable() string {
+	return r.variable
+}
+
 func (r *valueResolver) CanResolve(_ *gin.Context, argumentType reflect.Type, argument int) bool {
-	return r.argumentPosition == argument && isScalar(argumentType)
+	if r.argumentType != nil {
+		return r.argumentType == argumentType && r.isBindable(argumentType)
+	}
+	return r.argumentPosition == argument && r.isBindable(argumentType)
+}
+
+// isBindable reports whether the type is a scalar, a slice or array of scalars,
+// or a map of scalars for query and form variables i.e. ?filter[name]=x.
+func (r *valueResolver) isBindable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return isConvertible(t.Elem())
+	case reflect.Map:
+		return (r.scope == ScopeQuery || r.scope == ScopeForm) && t.Key().Kind() == reflect.String && isConvertible(t.Elem())
+	}
+	return isConvertible(t)
+}
+
+// VerifyArgument converts the default value into the argument type once, a bad default is reported at registration.
+func (r *valueResolver) VerifyArgument(argumentType reflect.Type) error {
+	if r.defaultValue == nil {
+		return nil
+	}
+	v, err := r.defaultValueToArgumentType(r.defaultValue, argumentType)
+	if err != nil {
+		return fmt.Errorf("invalid default of %s variable '%s': %w", r.scope, r.variable, err)
+	}
+	r.defaults.Store(argumentType, v)
+	return nil
+}
+
+// defaultValueOf returns the default value converted into the argument type.
+func (r *valueResolver) defaultValueOf(argumentType reflect.Type) (reflect.Value, error) {
+	if v, converted := r.defaults.Load(argumentType); converted {
+		return copyDefault(v.(reflect.Value)), nil
+	}
+	return r.defaultValueToArgumentType(r.defaultValue, argumentType)
 }
 
-func (r *valueResolver) defaultValueToArgumentType(defaultValue any, argumentType reflect.Type) reflect.Value {
+func (r *valueResolver) defaultValueToArgumentType(defaultValue any, argumentType reflect.Type) (reflect.Value, error) {
 	if defaultValue == nil {
-		switch argumentType.Kind() {
-		case reflect.String:
-			return reflect.ValueOf("")
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			return reflect.ValueOf(0)
-		case reflect.Float32, reflect.Float64:
-			return reflect.ValueOf(0.0)
-		case reflect.Bool:
-			return reflect.ValueOf(false)
-		case reflect.Pointer:
-			return reflect.ValueOf(nil)
+		return reflect.Zero(argumentType), nil
+	}
+
+	// Defaults declared by tags, i.e. `ginx:"query=limit,default=20"`.
+	if s, isString := defaultValue.(string); isString {
+		if argumentType.Kind() == reflect.Slice || argumentType.Kind() == reflect.Array {
+			return r.resolveList(splitValues([]string{s}, r.separator), argumentType)
 		}
+		return r.resolveScalar(s, argumentType)
 	}
-	return reflect.ValueOf(defaultValue)
+
+	v := reflect.ValueOf(defaultValue)
+	if argumentType.Kind() == reflect.Pointer {
+		if converted, isConverted := convertDefault(v, argumentType.Elem()); isConverted {
+			p := reflect.New(argumentType.Elem())
+			p.Elem().Set(converted)
+			return p, nil
+		}
+	}
+	if converted, isConverted := convertDefault(v, argumentType); isConverted {
+		return converted, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("default value %v of %s is not convertible to %s", defaultValue, v.Type(), argumentType)
 }
 
 func (r *valueResolver) Resolve(ctx *gin.Context, argumentType reflect.Type) (reflect.Value, error) {
-	var val string
-	switch r.scope {
-	case ScopePath:
-		if v, exists := ctx.Params.Get(r.variable); exists {
-			val = v
-		} else {
-			return r.defaultValueToArgumentType(r.defaultValue, argumentType), nil
-		}
-	case ScopeQuery:
-		if v, exists := ctx.GetQuery(r.variable); exists {
-			val = v
-		} else {
-			return r.defaultValueToArgumentType(r.defaultValue, argumentType), nil
-		}
-	case ScopeHeader:
-		if _, exists := ctx.Request.Header[textproto.CanonicalMIMEHeaderKey(r.variable)]; exists {
-			val = ctx.GetHeader(r.variable)
-		} else {
-			return r.defaultValueToArgumentType(r.defaultValue, argumentType), nil
+	v, exists, err := r.resolve(ctx, argumentType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if !exists {
+		if r.required {
+			return reflect.Value{}, &MissingError{Variables: []Variable{{Scope: r.scope, Name: r.variable}}}
 		}
+		return r.defaultValueOf(argumentType)
 	}
+	return v, nil
+}
 
-	// Convert value to the argument type.
-	switch argumentType.Kind() {
-	case reflect.String:
-		return reflect.ValueOf(val), nil
-	case reflect.Bool:
-		b, err := strconv.ParseBool(val)
-		if err != nil {
-			return reflect.ValueOf(false), err
-		}
-		return reflect.ValueOf(b), nil
-	case reflect.Int:
-		i, err := strconv.Atoi(val)
-		if err != nil {
-			return reflect.ValueOf(0), err
+// resolve converts the variable into the type, the second value reports whether the variable is present.
+func (r *valueResolver) resolve(ctx *gin.Context, t reflect.Type) (reflect.Value, bool, error) {
+	if t.Kind() == reflect.Map {
+		values, exists := r.lookupMap(ctx)
+		if !exists {
+			return reflect.Value{}, false, nil
 		}
-		return reflect.ValueOf(i), nil
-	case reflect.Int8:
-		i, err := strconv.ParseInt(val, 10, 8)
-		if err != nil {
-			return reflect.ValueOf(0), err
+		m, err := r.resolveMap(values, t)
+		return m, true, err
+	}
+
+	// Path variables are single values, so they are converted without collecting values.
+	if r.scope == ScopePath && t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		val, exists := ctx.Params.Get(r.variable)
+		if !exists {
+			return reflect.Value{}, false, nil
 		}
-		return reflect.ValueOf(int8(i)), nil
-	case reflect.Int16:
-		i, err := strconv.ParseInt(val, 10, 16)
+		v, err := r.resolveScalar(val, t)
+		return v, true, err
+	}
+
+	values, exists, err := r.lookup(ctx)
+	if err != nil {
+		return reflect.Value{}, true, &BindingError{Scope: r.scope, Variable: r.variable, Value: values[0], Type: t, Err: err}
+	}
+	if !exists {
+		return reflect.Value{}, false, nil
+	}
+
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		list, err := r.resolveList(splitValues(values, r.separator), t)
+		return list, true, err
+	}
+
+	v, err := r.resolveScalar(values[0], t)
+	return v, true, err
+}
+
+// lookup returns values of the variable, the second value reports whether the variable is present.
+func (r *valueResolver) lookup(ctx *gin.Context) ([]string, bool, error) {
+	switch r.scope {
+	case ScopePath:
+		v, exists := ctx.Params.Get(r.variable)
+		return []string{v}, exists, nil
+	case ScopeQuery:
+		values, exists := ctx.GetQueryArray(r.variable)
+		return values, exists, nil
+	case ScopeHeader:
+		values, exists := ctx.Request.Header[textproto.CanonicalMIMEHeaderKey(r.variable)]
+		return values, exists, nil
+	case ScopeForm:
+		values, exists := ctx.GetPostFormArray(r.variable)
+		return values, exists, nil
+	case ScopeCookie:
+		v, err := ctx.Cookie(r.variable)
 		if err != nil {
-			return reflect.ValueOf(0), err
+			return nil, false, nil
 		}
-		return reflect.ValueOf(int16(i)), nil
-	case reflect.Int32:
-		i, err := strconv.ParseInt(val, 10, 32)
-		if err != nil {
-			return reflect.ValueOf(0), err
+		if r.cookieCodec != nil {
+			decoded, err := r.cookieCodec.Decode(r.variable, v)
+			if err != nil {
+				return []string{v}, true, err
+			}
+			v = decoded
 		}
-		return reflect.ValueOf(int32(i)), nil
-	case reflect.Int64:
-		i, err := strconv.ParseInt(val, 10, 64)
-		if err != nil {
-			return reflect.ValueOf(0), err
+		return []string{v}, true, nil
+	}
+	return nil, false, nil
+}
+
+func (r *valueResolver) resolveScalar(val string, argumentType reflect.Type) (reflect.Value, error) {
+	v, err := convertValue(val, argumentType, r.layout)
+	if err != nil {
+		return v, &BindingError{Scope: r.scope, Variable: r.variable, Value: val, Type: argumentType, Err: err}
+	}
+	return v, nil
+}
+
+func (r *valueResolver) resolveList(values []string, argumentType reflect.Type) (reflect.Value, error) {
+	var list reflect.Value
+	if argumentType.Kind() == reflect.Array {
+		if len(values) > argumentType.Len() {
+			err := fmt.Errorf("too many values [%d], expected at most %d", len(values), argumentType.Len())
+			return reflect.Value{}, &BindingError{Scope: r.scope, Variable: r.variable, Value: strings.Join(values, r.separator), Type: argumentType, Err: err}
 		}
-		return reflect.ValueOf(i), nil
-	case reflect.Float32:
-		f, err := strconv.ParseFloat(val, 32)
+		list = reflect.New(argumentType).Elem()
+	} else {
+		list = reflect.MakeSlice(argumentType, len(values), len(values))
+	}
+
+	for i, val := range values {
+		v, err := r.resolveScalar(val, argumentType.Elem())
 		if err != nil {
-			return reflect.ValueOf(0.0), err
+			return reflect.Value{}, err
 		}
-		return reflect.ValueOf(f), nil
-	case reflect.Float64:
-		f, err := strconv.ParseFloat(val, 64)
+		list.Index(i).Set(v)
+	}
+
+	return list, nil
+}
+
+// lookupMap returns deepObject style variables, i.e. ?filter[name]=x&filter[status]=active.
+func (r *valueResolver) lookupMap(ctx *gin.Context) (map[string]string, bool) {
+	switch r.scope {
+	case ScopeQuery:
+		return ctx.GetQueryMap(r.variable)
+	case ScopeForm:
+		return ctx.GetPostFormMap(r.variable)
+	}
+	return nil, false
+}
+
+func (r *valueResolver) resolveMap(values map[string]string, t reflect.Type) (reflect.Value, error) {
+	m := reflect.MakeMapWithSize(t, len(values))
+	for key, val := range values {
+		v, err := r.resolveScalar(val, t.Elem())
 		if err != nil {
-			return reflect.ValueOf(0.0), err
+			return reflect.Value{}, err
 		}
-		return reflect.ValueOf(f), nil
-	case reflect.Uint:
-		i, err := strconv.ParseUint(val, 10, 32)
-		if err != nil {
-			return reflect.ValueOf(0), err
+		m.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), v)
+	}
+
+	return m, nil
+}
+
+// splitValues splits every value by the separator, i.e. ["1,2", "3"] -> ["1", "2", "3"].
+func splitValues(values []string, separator string) []string {
+	if separator == "" {
+		return values
+	}
+
+	var out []string
+	for _, val := range values {
+		for _, v := range strings.Split(val, separator) {
+			out = append(out, strings.TrimSpace(v))
 		}
-		return reflect.ValueOf(uint(i)), nil
-	case reflect.Uint8:
-		i, err := strconv.ParseUint(val, 10, 8)
-		if err != nil {
-			return reflect.ValueOf(0), err
+	}
+	return out
+}
+
+// isDefaultConvertible reports whether a default value is convertible to the argument type without a change of meaning,
+// i.e. int to int64, but not int to string.
+func isDefaultConvertible(from, to reflect.Type) bool {
+	return from.Kind() == to.Kind() && from.ConvertibleTo(to) || isNumber(from) && isNumber(to)
+}
+
+// convertDefault converts the default value into the type, numbers are converted into integers only if the value is kept,
+// i.e. 20 into int8, but neither 3.7 nor 300 nor -1 into uint8.
+func convertDefault(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if !isDefaultConvertible(v.Type(), t) {
+		return reflect.Value{}, false
+	}
+	converted := v.Convert(t)
+	if isNumber(v.Type()) && isInteger(t) && (converted.Convert(v.Type()).Interface() != v.Interface() || isNegative(v) != isNegative(converted)) {
+		return reflect.Value{}, false
+	}
+	return converted, true
+}
+
+// copyDefault copies a converted default of a reference type, so handlers modifying it do not share the change.
+func copyDefault(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
 		}
-		return reflect.ValueOf(uint8(i)), nil
-	case reflect.Uint16:
-		i, err := strconv.ParseUint(val, 10, 16)
-		if err != nil {
-			return reflect.ValueOf(0), err
+		return reflect.AppendSlice(reflect.MakeSlice(v.Type(), 0, v.Len()), v)
+	case reflect.Map:
+		if v.IsNil() {
+			return v
 		}
-		return reflect.ValueOf(uint16(i)), nil
-	case reflect.Uint32:
-		i, err := strconv.ParseUint(val, 10, 32)
-		if err != nil {
-			return reflect.ValueOf(0), err
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), iter.Value())
 		}
-		return reflect.ValueOf(uint32(i)), nil
-	case reflect.Uint64:
-		i, err := strconv.ParseUint(val, 10, 64)
-		if err != nil {
-			return reflect.ValueOf(0), err
+		return m
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
 		}
-		return reflect.ValueOf(i), nil
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(v.Elem())
+		return p
 	}
+	return v
+}
+
+func isInteger(t reflect.Type) bool {
+	return isNumber(t) && t.Kind() != reflect.Float32 && t.Kind() != reflect.Float64
+}
+
+func isNegative(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() < 0
+	}
+	return false
+}
 
-	return reflect.ValueOf(nil), errors.New("could not resolve")
+func isNumber(t reflect.Type) bool {
+	return isScalar(t) && t.Kind() != reflect.String && t.Kind() != reflect.Bool
 }
 
 func isScalar(t reflect.Type) bool {
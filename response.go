@@ -1,6 +1,9 @@
 package ginx
 
-import "reflect"
+import (
+	"net/http"
+	"reflect"
+)
 
 type Response interface {
 	Status() int
@@ -9,12 +12,18 @@ type Response interface {
 	SetContentType(contentType string)
 	Body() any
 	SetBody(body any)
+	// Header returns response headers, i.e. Location or Cache-Control.
+	Header() http.Header
+	Cookies() []*http.Cookie
+	SetCookie(cookie *http.Cookie)
 }
 
 type response struct {
 	status      int
 	contentType string
 	body        any
+	header      http.Header
+	cookies     []*http.Cookie
 }
 
 func NewResponse(status int) Response {
@@ -31,6 +40,24 @@ func OKResponse() Response {
 	}
 }
 
+// Created returns 201 Created with the Location header pointing to the created resource.
+//
+//	controller.POST("/users", func(u newUser) ginx.Response {
+//		user := users.Create(u)
+//		return ginx.Created("/users/"+user.ID, user)
+//	})
+func Created(location string, body any) Response {
+	res := NewResponse(http.StatusCreated)
+	res.Header().Set("Location", location)
+	res.SetBody(body)
+	return res
+}
+
+// NoContent returns 204 No Content.
+func NoContent() Response {
+	return NewResponse(http.StatusNoContent)
+}
+
 func (r *response) Status() int {
 	return r.status
 }
@@ -58,9 +85,30 @@ func (r *response) SetBody(body any) {
 	r.body = body
 }
 
+func (r *response) Header() http.Header {
+	if r.header == nil {
+		r.header = http.Header{}
+	}
+	return r.header
+}
+
+func (r *response) Cookies() []*http.Cookie {
+	return r.cookies
+}
+
+func (r *response) SetCookie(cookie *http.Cookie) {
+	r.cookies = append(r.cookies, cookie)
+}
+
 func (response) fromValue(v reflect.Value) Response {
 	res := NewResponse(200)
 
+	// Readers are streamed, see Controller.streamResponse.
+	if v.Type().Implements(readerType) && !(v.Kind() == reflect.Pointer && v.IsNil()) {
+		res.SetBody(v.Interface())
+		return res
+	}
+
 	switch v.Type().Kind() {
 	// Number is a status code
 	case reflect.Int:
@@ -81,7 +129,7 @@ func (response) fromValue(v reflect.Value) Response {
 		} else {
 			return response{}.fromValue(reflect.Indirect(v))
 		}
-	case reflect.Map:
+	case reflect.Map, reflect.Chan, reflect.Func:
 		res.SetBody(v.Interface())
 	case reflect.Array, reflect.Slice:
 		if v.Len() == 0 {
@@ -91,12 +139,9 @@ func (response) fromValue(v reflect.Value) Response {
 		}
 	case reflect.Interface, reflect.Struct:
 		if r, isResponse := v.Interface().(Response); isResponse {
-			res.SetStatus(r.Status())
-			res.SetContentType(r.ContentType())
-			res.SetBody(r.Body())
-		} else {
-			res.SetBody(v.Interface())
+			return r
 		}
+		res.SetBody(v.Interface())
 	}
 
 	return res
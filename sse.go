@@ -0,0 +1,151 @@
+package ginx
+
+import (
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// MIMEEventStream is the Server-Sent Events media type.
+const MIMEEventStream = "text/event-stream"
+
+// DefaultHeartbeat is the interval of heartbeat comments keeping idle event streams open.
+const DefaultHeartbeat = 15 * time.Second
+
+var eventType = reflect.TypeOf(Event{})
+
+// Event is a Server-Sent Event.
+// A handler returning a channel of events is served as text/event-stream until the channel is closed
+// or the client disconnects:
+//
+//	controller.GET("/notifications", func(ctx context.Context, lastID ginx.LastEventID) <-chan ginx.Event {
+//		events := make(chan ginx.Event)
+//		go func() {
+//			defer close(events)
+//			for n := range notifications.Since(ctx, string(lastID)) {
+//				events <- ginx.Event{ID: n.ID, Event: "notification", Data: n}
+//			}
+//		}()
+//		return events
+//	})
+//
+// Producers should stop on the request context cancellation, the channel is not read after the client disconnects.
+type Event struct {
+	ID    string
+	Event string
+	// Data is written as is if it is a string or a number, otherwise it is encoded as JSON.
+	Data any
+	// Retry is the reconnection time for the client.
+	Retry time.Duration
+}
+
+// LastEventID is the Last-Event-ID header value sent by a reconnecting client, see LastEventIDResolver.
+type LastEventID string
+
+// Heartbeat sets the interval of heartbeat comments sent to idle event streams, zero disables heartbeats.
+func Heartbeat(interval time.Duration) func(*handler) {
+	return func(h *handler) {
+		h.heartbeat = &interval
+	}
+}
+
+// isEventStreamType reports whether the type is a receivable channel of events.
+func isEventStreamType(t reflect.Type) bool {
+	if t.Kind() != reflect.Chan || t.ChanDir()&reflect.RecvDir == 0 {
+		return false
+	}
+	return t.Elem() == eventType || t.Elem().Kind() == reflect.Pointer && t.Elem().Elem() == eventType
+}
+
+// streamEvents writes events received from the channel until it is closed or the client disconnects.
+func (c *Controller) streamEvents(ctx *gin.Context, events reflect.Value, status int) {
+	ctx.Header("Content-Type", MIMEEventStream)
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// Disable response buffering by nginx.
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(status)
+	ctx.Writer.Flush()
+
+	heartbeat := DefaultHeartbeat
+	if interval, exists := ctx.Get("ginx_heartbeat"); exists {
+		heartbeat = interval.(time.Duration)
+	}
+
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: events},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Request.Context().Done())},
+		// A nil channel is never ready, it is replaced by a ticker if heartbeats are enabled.
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf((<-chan time.Time)(nil))},
+	}
+	if heartbeat > 0 {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		cases[2].Chan = reflect.ValueOf(ticker.C)
+	}
+
+	for {
+		chosen, v, received := reflect.Select(cases)
+
+		var err error
+		switch chosen {
+		case 0:
+			if !received {
+				return
+			}
+			err = writeEvent(ctx.Writer, v)
+		case 1:
+			return
+		case 2:
+			_, err = ctx.Writer.WriteString(":\n\n")
+		}
+
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, v reflect.Value) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	e := v.Interface().(Event)
+
+	return sse.Encode(w, sse.Event{
+		Id:    e.ID,
+		Event: e.Event,
+		Retry: uint(e.Retry / time.Millisecond),
+		Data:  e.Data,
+	})
+}
+
+type lastEventIDResolver struct{}
+
+// LastEventIDResolver creates a resolver which can inject the Last-Event-ID header into LastEventID arguments.
+func LastEventIDResolver() *lastEventIDResolver {
+	return &lastEventIDResolver{}
+}
+
+func (r *lastEventIDResolver) Priority() int {
+	return 200
+}
+
+func (r *lastEventIDResolver) Static() bool {
+	return true
+}
+
+func (r *lastEventIDResolver) CanResolve(_ *gin.Context, argumentType reflect.Type, _ int) bool {
+	return argumentType == reflect.TypeOf(LastEventID(""))
+}
+
+func (r *lastEventIDResolver) Resolve(ctx *gin.Context, _ reflect.Type) (reflect.Value, error) {
+	return reflect.ValueOf(LastEventID(ctx.GetHeader("Last-Event-ID"))), nil
+}
@@ -0,0 +1,243 @@
+package ginx
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx/renderer"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// MIMENDJSON is the newline delimited JSON media type, streamed responses are written one JSON value per line.
+const MIMENDJSON = "application/x-ndjson"
+
+var readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+
+// elementEncoder writes elements of a streamed response body.
+type elementEncoder interface {
+	Encode(v any) error
+	Close() error
+}
+
+// isStreamType reports whether the type is a receivable channel or an iterator func(yield func(T) bool).
+func isStreamType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Chan:
+		return t.ChanDir()&reflect.RecvDir != 0
+	case reflect.Func:
+		if t.NumIn() != 1 || t.NumOut() != 0 {
+			return false
+		}
+		yield := t.In(0)
+		return yield.Kind() == reflect.Func && yield.NumIn() == 1 && yield.NumOut() == 1 && yield.Out(0).Kind() == reflect.Bool
+	}
+	return false
+}
+
+// streamElementType returns the type of streamed elements.
+func streamElementType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Chan {
+		return t.Elem()
+	}
+	return t.In(0).In(0)
+}
+
+// streamResponse writes readers, channels and iterators chunk by chunk, flushing every chunk.
+// Streaming stops when the client disconnects. It reports false if the body can not be streamed.
+//
+// Channels and iterators are encoded according to the content type:
+// a JSON array by default, newline delimited JSON for application/x-ndjson, or CSV rows for text/csv.
+// An element implementing error stops the stream, the error is recorded by gin.Context.Error.
+// A stream stopped by an error or an encoding failure is not terminated, i.e. the JSON array is not closed,
+// and the response is aborted by http.ErrAbortHandler, so the client does not take a truncated body for a complete one.
+//
+// The channel is not read after the client disconnects, so producers must stop sending
+// once the request context is cancelled, otherwise they block forever:
+//
+//	select {
+//	case ch <- row:
+//	case <-ctx.Done():
+//		return
+//	}
+func (c *Controller) streamResponse(ctx *gin.Context, res Response, contentType string) bool {
+	if r, isReader := res.Body().(io.Reader); isReader {
+		if closer, isCloser := r.(io.Closer); isCloser {
+			defer closer.Close()
+		}
+		ctx.Header("Content-Type", contentType)
+		ctx.Status(res.Status())
+		c.streamReader(ctx, r)
+		return true
+	}
+
+	v := reflect.ValueOf(res.Body())
+	if !v.IsValid() || !isStreamType(v.Type()) || v.IsNil() {
+		return false
+	}
+
+	if isEventStreamType(v.Type()) {
+		c.streamEvents(ctx, v, res.Status())
+		return true
+	}
+
+	encoder, contentType := c.newElementEncoder(ctx.Writer, contentType)
+	ctx.Header("Content-Type", contentType)
+	ctx.Status(res.Status())
+
+	// failed marks a stream stopped by an error element or an encoding failure.
+	failed := false
+
+	// encode writes the element and reports whether streaming should continue.
+	encode := func(elem any) bool {
+		if err, isError := elem.(error); isError {
+			_ = ctx.Error(err)
+			failed = true
+			return false
+		}
+		if err := encoder.Encode(elem); err != nil {
+			_ = ctx.Error(err)
+			failed = true
+			return false
+		}
+		ctx.Writer.Flush()
+		return ctx.Request.Context().Err() == nil
+	}
+
+	if v.Kind() == reflect.Chan {
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: v},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Request.Context().Done())},
+		}
+		for {
+			chosen, elem, received := reflect.Select(cases)
+			if chosen == 1 || !received || !encode(elem.Interface()) {
+				break
+			}
+		}
+	} else {
+		yield := reflect.MakeFunc(v.Type().In(0), func(args []reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.ValueOf(encode(args[0].Interface()))}
+		})
+		v.Call([]reflect.Value{yield})
+	}
+
+	if failed {
+		ctx.Writer.Flush()
+		panic(http.ErrAbortHandler)
+	}
+
+	if err := encoder.Close(); err != nil {
+		_ = ctx.Error(err)
+	}
+	ctx.Writer.Flush()
+
+	return true
+}
+
+func (c *Controller) streamReader(ctx *gin.Context, r io.Reader) {
+	buf := make([]byte, 32*1024)
+	for ctx.Request.Context().Err() == nil {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := ctx.Writer.Write(buf[:n]); werr != nil {
+				_ = ctx.Error(werr)
+				return
+			}
+			ctx.Writer.Flush()
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+}
+
+// newElementEncoder creates an encoder for the content type and returns the content type of the stream.
+func (c *Controller) newElementEncoder(w io.Writer, contentType string) (elementEncoder, string) {
+	mediaType, _ := parseMediaType(contentType)
+
+	switch mediaType {
+	case MIMENDJSON:
+		return &jsonLinesEncoder{w: w, render: c.renderJSON}, contentType
+	case "text/csv":
+		return &csvEncoder{renderer.NewCSVEncoder(w)}, contentType
+	}
+
+	if mediaType != gin.MIMEJSON && !strings.HasSuffix(mediaType, "+json") {
+		contentType = gin.MIMEJSON
+	}
+	return &jsonArrayEncoder{w: w, render: c.renderJSON}, contentType
+}
+
+// renderJSON encodes the value by the JSON renderer of the controller.
+func (c *Controller) renderJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.findRenderer(gin.MIMEJSON).Render(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonArrayEncoder writes elements as a JSON array: [<elem>,<elem>].
+type jsonArrayEncoder struct {
+	w      io.Writer
+	render func(any) ([]byte, error)
+	n      int
+}
+
+func (e *jsonArrayEncoder) Encode(v any) error {
+	data, err := e.render(v)
+	if err != nil {
+		return err
+	}
+
+	sep := ","
+	if e.n == 0 {
+		sep = "["
+	}
+	e.n++
+
+	_, err = e.w.Write(append([]byte(sep), data...))
+	return err
+}
+
+func (e *jsonArrayEncoder) Close() error {
+	end := "]"
+	if e.n == 0 {
+		end = "[]"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+// jsonLinesEncoder writes elements as newline delimited JSON.
+type jsonLinesEncoder struct {
+	w      io.Writer
+	render func(any) ([]byte, error)
+}
+
+func (e *jsonLinesEncoder) Encode(v any) error {
+	data, err := e.render(v)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+func (e *jsonLinesEncoder) Close() error {
+	return nil
+}
+
+type csvEncoder struct {
+	*renderer.CSVEncoder
+}
+
+func (e *csvEncoder) Close() error {
+	return nil
+}
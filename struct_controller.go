@@ -0,0 +1,129 @@
+package ginx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Route describes a route served by a struct controller.
+type Route struct {
+	Method string
+	Path   string
+	// Handler is a name of the struct method or a handler function.
+	Handler HandlerFunc
+	Options []HandlerOption
+}
+
+// RouteProvider is implemented by struct controllers which declare their routes explicitly.
+type RouteProvider interface {
+	Routes() []Route
+}
+
+// routeMethodPrefixes maps method name prefixes to HTTP methods for the naming convention.
+var routeMethodPrefixes = []struct {
+	prefix string
+	method string
+}{
+	{"Get", "GET"},
+	{"Post", "POST"},
+	{"Put", "PUT"},
+	{"Patch", "PATCH"},
+	{"Delete", "DELETE"},
+	{"Head", "HEAD"},
+	{"Options", "OPTIONS"},
+}
+
+// Register registers exported methods of the struct as request handlers.
+//
+// If the struct implements RouteProvider, only the declared routes are registered:
+//
+//	func (uc *UserController) Routes() []ginx.Route {
+//		return []ginx.Route{
+//			{Method: "GET", Path: "/users/:id", Handler: "FindUser", Options: []ginx.HandlerOption{resolver.Path("id", 1)}},
+//			{Method: "POST", Path: "/users", Handler: uc.CreateUser},
+//		}
+//	}
+//
+// Otherwise, routes are discovered by the method naming convention <HTTP method><Path>,
+// the path is the kebab-cased rest of the name: GetUsers -> GET /users, PostUserOrder -> POST /user-order.
+func (c *Controller) Register(obj any) error {
+	if provider, isProvider := obj.(RouteProvider); isProvider {
+		for _, route := range provider.Routes() {
+			handlerFunc := route.Handler
+			if methodName, isName := route.Handler.(string); isName {
+				method := reflect.ValueOf(obj).MethodByName(methodName)
+				if !method.IsValid() {
+					return fmt.Errorf("%T has no method '%s'", obj, methodName)
+				}
+				handlerFunc = method.Interface()
+			}
+
+			if err := c.registerHandler(route.Method, route.Path, handlerFunc, route.Options...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	objReflect := reflect.ValueOf(obj)
+	for i := 0; i < objReflect.NumMethod(); i++ {
+		methodName := objReflect.Type().Method(i).Name
+
+		httpMethod, path, isRoute := routeByMethodName(methodName)
+		if !isRoute {
+			continue
+		}
+
+		if err := c.registerHandler(httpMethod, path, objReflect.Method(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MustRegister is like Register but panics if the struct can not be registered.
+func (c *Controller) MustRegister(obj any) {
+	if err := c.Register(obj); err != nil {
+		panic(err)
+	}
+}
+
+func routeByMethodName(name string) (string, string, bool) {
+	for _, p := range routeMethodPrefixes {
+		if !strings.HasPrefix(name, p.prefix) {
+			continue
+		}
+
+		rest := name[len(p.prefix):]
+		if rest == "" {
+			return p.method, "/", true
+		}
+		if !unicode.IsUpper(rune(rest[0])) {
+			continue
+		}
+		return p.method, "/" + kebabCase(rest), true
+	}
+	return "", "", false
+}
+
+// kebabCase converts the Go name into the kebab case: UserOrders -> user-orders, HTTPStatus -> http-status.
+func kebabCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteRune('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
@@ -65,3 +65,10 @@ func (t *Tester) GET(url string, headers map[string]string) *httptest.ResponseRe
 	t.router.ServeHTTP(w, req)
 	return w
 }
+
+// Do serves the given request, i.e. a request with cookies or a custom content type.
+func (t *Tester) Do(req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	t.router.ServeHTTP(w, req)
+	return w
+}
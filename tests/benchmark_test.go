@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/resolver"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newBenchmarkRouter() *gin.Engine {
+	r := gin.New()
+	c := ginx.NewDefaultController(r)
+
+	c.GET("/users/:id", func(id int, page int, token string, req *http.Request) string {
+		return "user"
+	}, resolver.Path("id", 1), resolver.Query("page", 2), resolver.Header("token", 3))
+
+	c.GET("/orders", func(o order) string {
+		return o.Extra
+	})
+
+	return r
+}
+
+func benchmarkRequest(b *testing.B, url string) {
+	r := newBenchmarkRouter()
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set("token", "1234567890")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkHandleRequest_ValueResolvers(b *testing.B) {
+	benchmarkRequest(b, "/users/42?page=3")
+}
+
+func BenchmarkHandleRequest_StructResolver(b *testing.B) {
+	benchmarkRequest(b, "/orders?extra=promotion")
+}
+
+func BenchmarkHandleRequest_Typed(b *testing.B) {
+	r := gin.New()
+	c := ginx.NewController(r)
+	ginx.MustHandle(c, "GET", "/orders", func(ctx context.Context, o order) (string, error) {
+		return o.Extra, nil
+	})
+
+	req, _ := http.NewRequest("GET", "/orders?extra=promotion", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
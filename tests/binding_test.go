@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type bindingErrorResponse struct {
+	Code    string              `json:"code"`
+	Message string              `json:"message"`
+	Details ginx.BindingDetails `json:"details"`
+}
+
+var bindingController *ginx.Controller
+
+func init() {
+	bindingController = ginx.NewController(gin.New())
+	bindingController.ContentType = gin.MIMEJSON
+	bindingController.Use(resolver.Struct())
+
+	bindingController.GET("/posts", func(page int) string {
+		return "posts"
+	}, resolver.Query("page", 1))
+	bindingController.POST("/orders", func(o order) string {
+		return "created"
+	})
+}
+
+func Test_BindingError_Query(t *testing.T) {
+	res := bindingController.Tester().GET("/posts?page=abc", nil)
+
+	assert.Equal(t, 400, res.Code)
+
+	var body bindingErrorResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(t, "binding_error", body.Code)
+	assert.Equal(t, "invalid query variable 'page'", body.Message)
+	assert.Equal(t, ginx.BindingDetails{Scope: "query", Variable: "page", Value: "abc", Type: "int"}, body.Details)
+}
+
+func Test_BindingError_Body(t *testing.T) {
+	res := bindingController.Tester().POST("/orders", []byte(`{"id":"not a number"`))
+
+	assert.Equal(t, 400, res.Code)
+
+	var body bindingErrorResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(t, "body", body.Details.Scope)
+	assert.Equal(t, "tests.order", body.Details.Type)
+}
+
+func Test_Binding_ErrorBodyWithoutRenderer(t *testing.T) {
+	c := ginx.NewDefaultController(gin.New())
+	c.GET("/items", func(p int) string {
+		return "ok"
+	}, resolver.Query("p", 1))
+
+	res := c.Tester().GET("/items?p=abc", nil)
+
+	assert.Equal(t, 400, res.Code)
+	assert.Equal(t, "application/json; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.Contains(t, res.Body.String(), `"code":"binding_error"`)
+}
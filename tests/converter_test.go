@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+type slug string
+
+// version is converted by a registered converter.
+type version struct {
+	Major, Minor int
+}
+
+func init() {
+	resolver.RegisterConverter(func(s string) (version, error) {
+		var v version
+		_, err := fmt.Sscanf(s, "v%d.%d", &v.Major, &v.Minor)
+		return v, err
+	})
+}
+
+type eventFilter struct {
+	Since   time.Time     `ginx:"query=since,layout=2006-01-02"`
+	Timeout time.Duration `ginx:"query=timeout"`
+	Limit   *uint8        `ginx:"query=limit"`
+	Client  netip.Addr    `ginx:"header=X-Client-IP"`
+	Version version       `ginx:"query=v"`
+}
+
+var converterController *ginx.Controller
+
+func init() {
+	converterController = ginx.NewController(gin.New())
+	converterController.Use(resolver.Struct())
+
+	converterController.GET("/numbers", func(a int8, b uint16, c float32) string {
+		return fmt.Sprintf("%d:%d:%g", a, b, c)
+	}, resolver.Query("a", 1), resolver.Query("b", 2), resolver.Query("c", 3))
+
+	converterController.GET("/optional", func(page *int) string {
+		if page == nil {
+			return "absent"
+		}
+		return fmt.Sprintf("%d", *page)
+	}, resolver.Query("page", 1))
+
+	converterController.GET("/name", func(name *string) string {
+		if name == nil {
+			return "absent"
+		}
+		return fmt.Sprintf("%q", *name)
+	}, resolver.Query("name", 1))
+
+	converterController.GET("/since", func(since time.Time) string {
+		return since.Format(time.RFC3339)
+	}, resolver.Query("since", 1).Layout("2006-01-02"))
+
+	converterController.GET("/articles/:slug", func(s slug, addr netip.Addr) string {
+		return string(s) + "@" + addr.String()
+	}, resolver.Path("slug", 1), resolver.Header("X-Client-IP", 2))
+
+	converterController.GET("/events", func(f eventFilter) string {
+		limit := "nil"
+		if f.Limit != nil {
+			limit = fmt.Sprintf("%d", *f.Limit)
+		}
+		return strings.Join([]string{
+			f.Since.Format("2006-01-02"), f.Timeout.String(), limit, f.Client.String(), fmt.Sprintf("%d.%d", f.Version.Major, f.Version.Minor),
+		}, " ")
+	})
+}
+
+func Test_Converter_Numbers(t *testing.T) {
+	res := converterController.Tester().GET("/numbers?a=-8&b=16&c=1.5", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "-8:16:1.5", res.Body.String())
+}
+
+func Test_Converter_NumberOverflow(t *testing.T) {
+	res := converterController.Tester().GET("/numbers?a=300&b=1&c=1", nil)
+
+	assert.Equal(t, 400, res.Code)
+}
+
+func Test_Converter_PointerAbsentVsEmpty(t *testing.T) {
+	assert.Equal(t, "absent", converterController.Tester().GET("/optional", nil).Body.String())
+	assert.Equal(t, "2", converterController.Tester().GET("/optional?page=2", nil).Body.String())
+	assert.Equal(t, "absent", converterController.Tester().GET("/name", nil).Body.String())
+	assert.Equal(t, `""`, converterController.Tester().GET("/name?name=", nil).Body.String())
+}
+
+func Test_Converter_TimeLayout(t *testing.T) {
+	res := converterController.Tester().GET("/since?since=2024-05-01", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "2024-05-01T00:00:00Z", res.Body.String())
+}
+
+func Test_Converter_NamedTypeAndTextUnmarshaler(t *testing.T) {
+	res := converterController.Tester().GET("/articles/hello-world", map[string]string{"X-Client-IP": "10.0.0.1"})
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "hello-world@10.0.0.1", res.Body.String())
+}
+
+func Test_Converter_StructTags(t *testing.T) {
+	res := converterController.Tester().GET("/events?since=2024-05-01&timeout=1m30s&limit=10&v=v1.2", map[string]string{"X-Client-IP": "::1"})
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "2024-05-01 1m30s 10 ::1 1.2", res.Body.String())
+}
+
+func Test_Converter_StructTagsAbsent(t *testing.T) {
+	res := converterController.Tester().GET("/events", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "0001-01-01 0s nil invalid IP 0.0", res.Body.String())
+}
+
+func Test_Converter_StructTagsError(t *testing.T) {
+	res := converterController.Tester().GET("/events?timeout=soon", nil)
+
+	assert.Equal(t, 400, res.Code)
+	assert.Contains(t, res.Body.String(), "timeout")
+}
+
+func Test_Converter_OpenAPI(t *testing.T) {
+	doc := converterController.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	params := doc.Paths["/articles/{slug}"].Get.Parameters
+	assert.Equal(t, "string", params[1].Schema.Type)
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+var cookieSecret = []byte("secret")
+
+type sessionRequest struct {
+	Session string `ginx:"cookie=session"`
+	Theme   string `ginx:"cookie=theme"`
+}
+
+var cookieController *ginx.Controller
+
+// defaultCookieController and signedCookieGroup replace the default struct resolver by the signing one.
+var defaultCookieController, signedCookieGroup *ginx.Controller
+
+func init() {
+	cookieController = ginx.NewController(gin.New())
+	cookieController.Use(resolver.Struct().CookieCodec(resolver.SignedCookieCodec(cookieSecret)))
+
+	cookieController.GET("/visits", func(visits int) string {
+		return strconv.Itoa(visits + 1)
+	}, resolver.Cookie("visits", 1))
+	cookieController.GET("/me", func(session string) string {
+		return session
+	}, resolver.Cookie("session", 1).Codec(resolver.SignedCookieCodec(cookieSecret)))
+	cookieController.GET("/session", func(s sessionRequest) string {
+		return s.Session
+	})
+
+	defaultCookieController = ginx.NewDefaultController(gin.New())
+	defaultCookieController.Use(resolver.Struct().CookieCodec(resolver.SignedCookieCodec(cookieSecret)))
+	defaultCookieController.GET("/session", func(s sessionRequest) string {
+		return s.Session
+	})
+
+	signedCookieGroup = ginx.NewDefaultController(gin.New()).Group("/signed", resolver.Struct().CookieCodec(resolver.SignedCookieCodec(cookieSecret)))
+	signedCookieGroup.GET("/session", func(s sessionRequest) string {
+		return s.Session
+	})
+}
+
+func cookieRequest(c *ginx.Controller, url string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", url, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	return c.Tester().Do(req)
+}
+
+func signedCookie(name, value string) *http.Cookie {
+	encoded, _ := resolver.SignedCookieCodec(cookieSecret).Encode(name, value)
+	return &http.Cookie{Name: name, Value: encoded}
+}
+
+func Test_Cookie_Value(t *testing.T) {
+	res := cookieRequest(cookieController, "/visits", &http.Cookie{Name: "visits", Value: "3"})
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "4", res.Body.String())
+}
+
+func Test_Cookie_Signed(t *testing.T) {
+	res := cookieRequest(cookieController, "/me", signedCookie("session", "abc"))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "abc", res.Body.String())
+}
+
+func Test_Cookie_Tampered(t *testing.T) {
+	cookie := signedCookie("session", "abc")
+	cookie.Value = "admin" + cookie.Value[3:]
+
+	res := cookieRequest(cookieController, "/me", cookie)
+
+	assert.Equal(t, 400, res.Code)
+}
+
+func Test_Cookie_SignatureBoundToName(t *testing.T) {
+	cookie := signedCookie("other", "abc")
+	cookie.Name = "session"
+
+	res := cookieRequest(cookieController, "/me", cookie)
+
+	assert.Equal(t, 400, res.Code)
+}
+
+func Test_Cookie_StructTag(t *testing.T) {
+	res := cookieRequest(cookieController, "/session", signedCookie("session", "abc"))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "abc", res.Body.String())
+}
+
+func Test_Cookie_StructTagTampered(t *testing.T) {
+	res := cookieRequest(cookieController, "/session", &http.Cookie{Name: "session", Value: "abc"})
+
+	assert.Equal(t, 400, res.Code)
+}
+
+func Test_Cookie_DefaultControllerStructTag(t *testing.T) {
+	res := cookieRequest(defaultCookieController, "/session", signedCookie("session", "abc"))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "abc", res.Body.String())
+}
+
+func Test_Cookie_DefaultControllerStructTagForged(t *testing.T) {
+	res := cookieRequest(defaultCookieController, "/session", &http.Cookie{Name: "session", Value: "admin.forged"})
+
+	assert.Equal(t, 400, res.Code)
+}
+
+func Test_Cookie_GroupStructTagForged(t *testing.T) {
+	res := cookieRequest(signedCookieGroup, "/signed/session", &http.Cookie{Name: "session", Value: "admin.forged"})
+
+	assert.Equal(t, 400, res.Code)
+
+	res = cookieRequest(signedCookieGroup, "/signed/session", signedCookie("session", "abc"))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "abc", res.Body.String())
+}
+
+func Test_Cookie_OpenAPI(t *testing.T) {
+	doc := cookieController.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	params := doc.Paths["/session"].Get.Parameters
+	assert.Len(t, params, 2)
+	assert.Equal(t, "cookie", params[0].In)
+	assert.Equal(t, "session", params[0].Name)
+}
@@ -0,0 +1,222 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type diConfig struct {
+	Greeting string
+}
+
+type greeter interface {
+	Greet(name string) string
+}
+
+type configGreeter struct {
+	config *diConfig
+}
+
+func (g *configGreeter) Greet(name string) string {
+	return g.config.Greeting + ", " + name
+}
+
+type greetingKey struct{}
+
+type requestLog struct {
+	ID      int
+	Request string
+}
+
+type auditor struct {
+	log *requestLog
+}
+
+var diController *ginx.Controller
+
+// diGreeters and diLogs count constructions of the greeter and requestLog services.
+var diGreeters, diLogs int
+
+func init() {
+	diController = ginx.NewController(gin.New())
+	diController.Use(resolver.Path("name", 1))
+	diController.Use(ginx.Provide(&diConfig{Greeting: "Hello"}))
+	diController.Use(ginx.Provide(func(config *diConfig) greeter {
+		diGreeters++
+		return &configGreeter{config: config}
+	}))
+	diController.Use(ginx.Provide(func(ctx *gin.Context) *requestLog {
+		diLogs++
+		return &requestLog{ID: diLogs, Request: ctx.Request.URL.Path}
+	}).PerRequest())
+	diController.Use(ginx.Provide(func(log *requestLog) *auditor {
+		return &auditor{log: log}
+	}).Transient())
+
+	diController.GET("/greet/:name", func(name string, g greeter) string {
+		return g.Greet(name)
+	})
+	diController.GET("/log", func(log *requestLog, a1 *auditor, a2 *auditor) string {
+		return fmt.Sprintf("%d:%s:%t:%t", log.ID, log.Request, a1.log == log && a2.log == log, a1 != a2)
+	})
+
+	api := diController.Group("/api")
+	api.Use(ginx.Provide(func(g greeter) context.Context {
+		return context.WithValue(context.Background(), greetingKey{}, g.Greet("group"))
+	}).Transient())
+	api.GET("/hello", func(ctx context.Context) string {
+		return ctx.Value(greetingKey{}).(string)
+	})
+}
+
+func Test_DI_Singleton(t *testing.T) {
+	assert.Equal(t, "Hello, john", diController.Tester().GET("/greet/john", nil).Body.String())
+	assert.Equal(t, "Hello, jane", diController.Tester().GET("/greet/jane", nil).Body.String())
+	assert.Equal(t, 1, diGreeters)
+}
+
+func Test_DI_PerRequestAndTransient(t *testing.T) {
+	logs := diLogs
+
+	assert.Equal(t, fmt.Sprintf("%d:/log:true:true", logs+1), diController.Tester().GET("/log", nil).Body.String())
+	assert.Equal(t, fmt.Sprintf("%d:/log:true:true", logs+2), diController.Tester().GET("/log", nil).Body.String())
+	assert.Equal(t, logs+2, diLogs)
+}
+
+func Test_DI_Group(t *testing.T) {
+	assert.Equal(t, "Hello, group", diController.Tester().GET("/api/hello", nil).Body.String())
+}
+
+func Test_DI_ConstructorError(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.Use(ginx.Provide(func() (*diConfig, error) {
+		return nil, errors.New("config is not available")
+	}))
+	c.GET("/config", func(config *diConfig) string {
+		return config.Greeting
+	})
+
+	res := c.Tester().GET("/config", nil)
+
+	assert.Equal(t, 500, res.Code)
+}
+
+func Test_DI_NotProvided(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.Use(ginx.Provide(&diConfig{}))
+
+	err := c.GET("/greet", func(g greeter) string {
+		return ""
+	})
+
+	assert.Error(t, err)
+}
+
+func Test_DI_SingletonDependsOnRequest(t *testing.T) {
+	c := ginx.NewController(gin.New())
+
+	assert.Panics(t, func() {
+		c.Use(ginx.Provide(func(ctx *gin.Context) *requestLog {
+			return &requestLog{}
+		}))
+	})
+}
+
+func Test_DI_Cycle(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.Use(ginx.Provide(func(a *auditor) *requestLog {
+		return &requestLog{}
+	}))
+	c.Use(ginx.Provide(func(l *requestLog) *auditor {
+		return &auditor{log: l}
+	}))
+	c.GET("/audit", func(a *auditor) string {
+		return "ok"
+	})
+
+	res := c.Tester().GET("/audit", nil)
+
+	assert.Equal(t, 500, res.Code)
+}
+
+type requestID struct {
+	Value string
+}
+
+type requestService struct {
+	id *requestID
+}
+
+func Test_DI_SingletonDependsOnPerRequestService(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.Use(ginx.Provide(func(ctx *gin.Context) *requestID {
+		return &requestID{Value: ctx.Query("id")}
+	}).PerRequest())
+
+	assert.PanicsWithError(t, "singleton *tests.requestService can not depend on the per-request service *tests.requestID", func() {
+		c.Use(ginx.Provide(func(id *requestID) *requestService {
+			return &requestService{id: id}
+		}))
+	})
+}
+
+func Test_DI_SingletonReachesTransientService(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.Use(ginx.Provide(func(s *requestService) *auditor {
+		return &auditor{}
+	}))
+	c.Use(ginx.Provide(func(id *requestID) *requestService {
+		return &requestService{id: id}
+	}))
+
+	assert.PanicsWithError(t, "singleton *tests.auditor can not depend on the transient service *tests.requestID", func() {
+		c.Use(ginx.Provide(func() *requestID {
+			return &requestID{}
+		}).Transient())
+	})
+}
+
+func Test_DI_SingletonDependsOnServiceProvidedToParentLater(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	api := c.Group("/api")
+	api.Use(ginx.Provide(func(id *requestID) *requestService {
+		return &requestService{id: id}
+	}))
+	c.Use(ginx.Provide(func(ctx *gin.Context) *requestID {
+		return &requestID{Value: ctx.Query("id")}
+	}).PerRequest())
+	api.GET("/c", func(s *requestService) string {
+		return s.id.Value
+	})
+
+	assert.Equal(t, 500, c.Tester().GET("/api/c?id=1", nil).Code)
+	assert.Equal(t, 500, c.Tester().GET("/api/c?id=2", nil).Code)
+}
+
+func Test_DI_ProvideNil(t *testing.T) {
+	assert.PanicsWithError(t, "service must not be nil", func() {
+		ginx.Provide(nil)
+	})
+	assert.PanicsWithError(t, "service *tests.diConfig must not be nil", func() {
+		ginx.Provide((*diConfig)(nil))
+	})
+}
+
+func Test_DI_ProvidedAfterHandler(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.Use(resolver.Struct())
+	api := c.Group("/api")
+	api.GET("/config", func(config diConfig) string {
+		return config.Greeting
+	})
+
+	assert.PanicsWithError(t, "service tests.diConfig is provided after the handler of GET /api/config taking it is registered", func() {
+		c.Use(ginx.Provide(diConfig{Greeting: "Hello"}))
+	})
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var assetsModTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+var downloadController *ginx.Controller
+
+func init() {
+	dir, err := os.MkdirTemp("", "ginx-download")
+	if err != nil {
+		panic(err)
+	}
+	reportPath := filepath.Join(dir, "report.txt")
+	if err := os.WriteFile(reportPath, []byte("quarterly report"), 0o644); err != nil {
+		panic(err)
+	}
+
+	downloadController = ginx.NewDefaultController(gin.New())
+	downloadController.ContentType = gin.MIMEJSON
+
+	downloadController.GET("/report", func() ginx.File {
+		return ginx.File{Path: reportPath, Name: "Q1 report.txt"}
+	})
+
+	downloadController.GET("/missing", func() ginx.File {
+		return ginx.File{Path: filepath.Join(dir, "missing.txt")}
+	})
+
+	downloadController.GET("/image", func() ginx.Attachment {
+		// MultiReader hides io.Seeker, so the attachment is not seekable.
+		return ginx.Attachment{Reader: io.MultiReader(strings.NewReader(string(pngHeader))), Name: "image", Size: int64(len(pngHeader)), ModTime: assetsModTime}
+	})
+
+	downloadController.Static("/assets", fstest.MapFS{
+		"index.html": {Data: []byte("<h1>home</h1>"), ModTime: assetsModTime},
+		"app.css":    {Data: []byte("body{}"), ModTime: assetsModTime},
+	})
+}
+
+func Test_Download_File(t *testing.T) {
+	res := downloadController.Tester().GET("/report", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="Q1 report.txt"`, res.Header().Get("Content-Disposition"))
+	assert.Equal(t, "bytes", res.Header().Get("Accept-Ranges"))
+	assert.Equal(t, "quarterly report", res.Body.String())
+}
+
+func Test_Download_FileRange(t *testing.T) {
+	res := downloadController.Tester().GET("/report", map[string]string{"Range": "bytes=0-8"})
+
+	assert.Equal(t, 206, res.Code)
+	assert.Equal(t, "bytes 0-8/16", res.Header().Get("Content-Range"))
+	assert.Equal(t, "quarterly", res.Body.String())
+}
+
+func Test_Download_FileNotFound(t *testing.T) {
+	res := downloadController.Tester().GET("/missing", nil)
+
+	assert.Equal(t, 404, res.Code)
+	assert.Contains(t, res.Body.String(), "file_not_found")
+}
+
+func Test_Download_Attachment(t *testing.T) {
+	res := downloadController.Tester().GET("/image", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "image/png", res.Header().Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=image", res.Header().Get("Content-Disposition"))
+	assert.Equal(t, "16", res.Header().Get("Content-Length"))
+	assert.Equal(t, pngHeader, res.Body.Bytes())
+}
+
+func Test_Download_AttachmentNotModified(t *testing.T) {
+	res := downloadController.Tester().GET("/image", map[string]string{"If-Modified-Since": assetsModTime.Format(http.TimeFormat)})
+
+	assert.Equal(t, 304, res.Code)
+	assert.Empty(t, res.Body.String())
+}
+
+func Test_Download_Static(t *testing.T) {
+	res := downloadController.Tester().GET("/assets/app.css", map[string]string{"Accept": "text/css"})
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "text/css; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.Empty(t, res.Header().Get("Content-Disposition"))
+	assert.Equal(t, "body{}", res.Body.String())
+
+	res = downloadController.Tester().GET("/assets/", nil)
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "<h1>home</h1>", res.Body.String())
+
+	res = downloadController.Tester().GET("/assets/app.css", map[string]string{"If-Modified-Since": assetsModTime.Format(http.TimeFormat)})
+	assert.Equal(t, 304, res.Code)
+
+	res = downloadController.Tester().GET("/assets/app.js", nil)
+	assert.Equal(t, 404, res.Code)
+}
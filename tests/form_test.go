@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type postForm struct {
+	Title  string                `ginx:"form=title"`
+	Rating int                   `ginx:"form=rating"`
+	Cover  *multipart.FileHeader `ginx:"form=cover"`
+}
+
+var formController *ginx.Controller
+
+func init() {
+	formController = ginx.NewController(gin.New())
+	formController.Use(resolver.Struct())
+	formController.Use(resolver.MultipartReader())
+
+	formController.POST("/comments", func(text string, likes int) string {
+		return fmt.Sprintf("%s:%d", text, likes)
+	}, resolver.Form("text", 1), resolver.Form("likes", 2))
+
+	formController.POST("/posts", func(p postForm) string {
+		if p.Cover == nil {
+			return fmt.Sprintf("%s:%d", p.Title, p.Rating)
+		}
+		return fmt.Sprintf("%s:%d:%s", p.Title, p.Rating, p.Cover.Filename)
+	})
+
+	formController.POST("/avatar", func(avatar *multipart.FileHeader) string {
+		if avatar == nil {
+			return "none"
+		}
+		return fmt.Sprintf("%s:%d", avatar.Filename, avatar.Size)
+	}, resolver.File("avatar", 1).MaxSize(10))
+
+	formController.POST("/profile", func(name string, avatar *multipart.FileHeader) string {
+		return fmt.Sprintf("%s:%s", name, avatar.Filename)
+	}, resolver.Form("name", 1), resolver.File("avatar", 2).MaxSize(10))
+
+	formController.POST("/photos", func(photos []*multipart.FileHeader) string {
+		return fmt.Sprintf("%d", len(photos))
+	}, resolver.File("photos", 1).MaxFiles(2))
+
+	formController.POST("/stream", func(r *multipart.Reader) string {
+		var names []string
+		for {
+			part, err := r.NextPart()
+			if err == io.EOF {
+				break
+			}
+			names = append(names, part.FormName())
+		}
+		return strings.Join(names, ",")
+	})
+}
+
+type formFile struct {
+	field   string
+	name    string
+	content string
+}
+
+func multipartRequest(url string, fields map[string]string, files ...formFile) *http.Request {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for k, v := range fields {
+		_ = w.WriteField(k, v)
+	}
+	for _, f := range files {
+		fw, _ := w.CreateFormFile(f.field, f.name)
+		_, _ = fw.Write([]byte(f.content))
+	}
+	_ = w.Close()
+
+	req, _ := http.NewRequest("POST", url, &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func urlencodedRequest(url string, values url.Values) *http.Request {
+	req, _ := http.NewRequest("POST", url, strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", gin.MIMEPOSTForm)
+	return req
+}
+
+func Test_Form_Values(t *testing.T) {
+	res := formController.Tester().Do(urlencodedRequest("/comments", url.Values{"text": {"nice"}, "likes": {"5"}}))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "nice:5", res.Body.String())
+}
+
+func Test_Form_ValueBindingError(t *testing.T) {
+	res := formController.Tester().Do(urlencodedRequest("/comments", url.Values{"text": {"nice"}, "likes": {"many"}}))
+
+	assert.Equal(t, 400, res.Code)
+}
+
+func Test_Form_StructTags(t *testing.T) {
+	res := formController.Tester().Do(urlencodedRequest("/posts", url.Values{"title": {"Hello"}, "rating": {"4"}}))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "Hello:4", res.Body.String())
+}
+
+func Test_Form_StructTagsMultipart(t *testing.T) {
+	req := multipartRequest("/posts", map[string]string{"title": "Hello"}, formFile{"cover", "cover.png", "png"})
+
+	res := formController.Tester().Do(req)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "Hello:0:cover.png", res.Body.String())
+}
+
+func Test_Form_File(t *testing.T) {
+	res := formController.Tester().Do(multipartRequest("/avatar", nil, formFile{"avatar", "me.png", "png"}))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "me.png:3", res.Body.String())
+}
+
+func Test_Form_FileMissing(t *testing.T) {
+	res := formController.Tester().Do(multipartRequest("/avatar", nil))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "none", res.Body.String())
+}
+
+func Test_Form_FileTooLarge(t *testing.T) {
+	res := formController.Tester().Do(multipartRequest("/avatar", nil, formFile{"avatar", "me.png", "0123456789abc"}))
+
+	assert.Equal(t, 413, res.Code)
+	assert.JSONEq(t, `{"code":"file_limit_exceeded","message":"form file 'avatar': file 'me.png' is too large [13 bytes], expected at most 10 bytes"}`, res.Body.String())
+}
+
+func Test_Form_FileAfterValue(t *testing.T) {
+	res := formController.Tester().Do(multipartRequest("/profile", map[string]string{"name": "john"}, formFile{"avatar", "me.png", "png"}))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "john:me.png", res.Body.String())
+}
+
+func Test_Form_Files(t *testing.T) {
+	res := formController.Tester().Do(multipartRequest("/photos", nil, formFile{"photos", "a.png", "a"}, formFile{"photos", "b.png", "b"}))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "2", res.Body.String())
+}
+
+func Test_Form_TooManyFiles(t *testing.T) {
+	req := multipartRequest("/photos", nil, formFile{"photos", "a.png", "a"}, formFile{"photos", "b.png", "b"}, formFile{"photos", "c.png", "c"})
+
+	res := formController.Tester().Do(req)
+
+	assert.Equal(t, 413, res.Code)
+}
+
+func Test_Form_MultipartReader(t *testing.T) {
+	res := formController.Tester().Do(multipartRequest("/stream", nil, formFile{"a", "a.txt", "a"}, formFile{"b", "b.txt", "b"}))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "a,b", res.Body.String())
+}
+
+func Test_Form_OpenAPI(t *testing.T) {
+	doc := formController.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	comments := doc.Paths["/comments"].Post.RequestBody.Content[gin.MIMEPOSTForm].Schema
+	assert.Equal(t, "integer", comments.Properties["likes"].Type)
+
+	posts := doc.Paths["/posts"].Post.RequestBody.Content[gin.MIMEMultipartPOSTForm].Schema
+	assert.Equal(t, "binary", posts.Properties["cover"].Format)
+
+	photos := doc.Paths["/photos"].Post.RequestBody.Content[gin.MIMEMultipartPOSTForm].Schema
+	assert.Equal(t, "array", photos.Properties["photos"].Type)
+}
+
+// countingReader counts bytes read from the request body.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func Test_Form_FileTooLargeIsNotBuffered(t *testing.T) {
+	req := multipartRequest("/avatar", nil, formFile{"avatar", "me.png", strings.Repeat("x", 8<<20)})
+	body := &countingReader{r: req.Body}
+	req.Body = io.NopCloser(body)
+
+	res := formController.Tester().Do(req)
+
+	assert.Equal(t, 413, res.Code)
+	assert.Less(t, body.n, 2<<20)
+}
+
+func Test_Form_FileAfterValueTooLargeIsNotBuffered(t *testing.T) {
+	req := multipartRequest("/profile", map[string]string{"name": "john"}, formFile{"avatar", "me.png", strings.Repeat("x", 8<<20)})
+	body := &countingReader{r: req.Body}
+	req.Body = io.NopCloser(body)
+
+	res := formController.Tester().Do(req)
+
+	assert.Equal(t, 413, res.Code)
+	assert.Less(t, body.n, 2<<20)
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+var errForbidden = errors.New("forbidden")
+
+var groupController *ginx.Controller
+
+func init() {
+	groupController = ginx.NewController(gin.New())
+	groupController.ContentType = gin.MIMEJSON
+	groupController.Use(resolver.HttpRequest())
+	groupController.Use(gin.HandlerFunc(func(ctx *gin.Context) {
+		ctx.Header("X-Root", "1")
+	}))
+	groupController.Use(ginx.ErrorStatus(errForbidden, http.StatusForbidden))
+
+	api := groupController.Group("/api", gin.HandlerFunc(func(ctx *gin.Context) {
+		ctx.Header("X-Api", "1")
+	}))
+
+	v1 := api.Group("/v1")
+	v1.GET("/users/:id", func(req *http.Request, id int) user {
+		return user{Login: req.URL.Path}
+	}, resolver.Path("id", 2))
+	v1.GET("/forbidden", func() error {
+		return errForbidden
+	})
+
+	v2 := api.Group("/v2")
+	v2.ContentType = gin.MIMEXML
+	v2.GET("/users", func() []user {
+		return []user{{Login: "root"}}
+	})
+}
+
+func Test_Group_InheritsFromParent(t *testing.T) {
+	res := groupController.Tester().GET("/api/v1/users/1", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "1", res.Header().Get("X-Root"))
+	assert.Equal(t, "1", res.Header().Get("X-Api"))
+	assert.Equal(t, "application/json; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.Equal(t, `{"login":"/api/v1/users/1"}`, res.Body.String())
+}
+
+func Test_Group_InheritsErrorMappings(t *testing.T) {
+	res := groupController.Tester().GET("/api/v1/forbidden", nil)
+
+	assert.Equal(t, 403, res.Code)
+}
+
+func Test_Group_OverridesContentType(t *testing.T) {
+	res := groupController.Tester().GET("/api/v2/users", nil)
+
+	assert.Equal(t, "application/xml; charset=utf-8", res.Header().Get("Content-Type"))
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+var errUserNotFound = errors.New("user not found")
+
+type conflictError struct {
+	login string
+}
+
+func (e *conflictError) Error() string {
+	return fmt.Sprintf("user '%s' already exists", e.login)
+}
+
+var httpErrorController *ginx.Controller
+
+func init() {
+	httpErrorController = ginx.NewController(gin.New())
+	httpErrorController.ContentType = gin.MIMEJSON
+	httpErrorController.Use(ginx.ErrorStatus(errUserNotFound, http.StatusNotFound))
+	httpErrorController.Use(ginx.ErrorTypeStatus[*conflictError](http.StatusConflict).WithMessage("user already exists"))
+
+	httpErrorController.GET("/http-error", func() (user, error) {
+		return user{}, ginx.NewHTTPError(http.StatusBadRequest, "invalid login").WithCode("invalid_login")
+	})
+	httpErrorController.GET("/sentinel", func() (user, error) {
+		return user{}, fmt.Errorf("load: %w", errUserNotFound)
+	})
+	httpErrorController.POST("/typed", func() error {
+		return &conflictError{login: "root"}
+	})
+	httpErrorController.GET("/unmapped", func() error {
+		return errors.New("boom")
+	})
+}
+
+func Test_HTTPError(t *testing.T) {
+	res := httpErrorController.Tester().GET("/http-error", nil)
+
+	assert.Equal(t, 400, res.Code)
+	assert.Equal(t, `{"code":"invalid_login","message":"invalid login"}`, res.Body.String())
+}
+
+func Test_HTTPError_SentinelMapping(t *testing.T) {
+	res := httpErrorController.Tester().GET("/sentinel", nil)
+
+	assert.Equal(t, 404, res.Code)
+	assert.Equal(t, `{"message":"Not Found"}`, res.Body.String())
+}
+
+func Test_HTTPError_TypeMapping(t *testing.T) {
+	res := httpErrorController.Tester().POST("/typed", nil)
+
+	assert.Equal(t, 409, res.Code)
+	assert.Equal(t, `{"message":"user already exists"}`, res.Body.String())
+}
+
+func Test_HTTPError_Unmapped(t *testing.T) {
+	res := httpErrorController.Tester().GET("/unmapped", nil)
+
+	assert.Equal(t, 500, res.Code)
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var negotiationController *ginx.Controller
+
+func init() {
+	negotiationController = ginx.NewController(gin.New())
+
+	negotiationController.GET("/posts", func() []blogPost {
+		return []blogPost{{Title: "First post", Content: "Hello, world"}}
+	}, ginx.Produces(gin.MIMEJSON, gin.MIMEXML))
+}
+
+func Test_Negotiation_DefaultsToFirstProducible(t *testing.T) {
+	res := negotiationController.Tester().GET("/posts", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "application/json; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", res.Header().Get("Vary"))
+}
+
+func Test_Negotiation_QualityValues(t *testing.T) {
+	res := negotiationController.Tester().GET("/posts", map[string]string{"Accept": "application/json;q=0.5, application/xml"})
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", res.Header().Get("Content-Type"))
+
+	res = negotiationController.Tester().GET("/posts", map[string]string{"Accept": "application/*;q=0.2, application/json;q=0"})
+	assert.Equal(t, "application/xml; charset=utf-8", res.Header().Get("Content-Type"))
+
+	res = negotiationController.Tester().GET("/posts", map[string]string{"Accept": "text/html, */*;q=0.1"})
+	assert.Equal(t, "application/json; charset=utf-8", res.Header().Get("Content-Type"))
+}
+
+func Test_Negotiation_NotAcceptable(t *testing.T) {
+	res := negotiationController.Tester().GET("/posts", map[string]string{"Accept": "text/html"})
+
+	assert.Equal(t, 406, res.Code)
+}
+
+func Test_Negotiation_OnlyForProducesHandlers(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.ContentType = gin.MIMEJSON
+	c.GET("/posts", func() []blogPost {
+		return []blogPost{{Title: "First post", Content: "Hello, world"}}
+	})
+	c.GET("/post", func() blogPost {
+		return blogPost{Title: "First post", Content: "Hello, world"}
+	}, ginx.ProduceXML())
+
+	res := c.Tester().GET("/posts", map[string]string{"Accept": "text/html"})
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "application/json; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.Empty(t, res.Header().Get("Vary"))
+
+	res = c.Tester().GET("/post", map[string]string{"Accept": "text/plain"})
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", res.Header().Get("Content-Type"))
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var openAPIController *ginx.Controller
+
+func init() {
+	openAPIController = ginx.NewController(gin.New())
+	openAPIController.ContentType = gin.MIMEJSON
+	openAPIController.Use(resolver.Struct())
+
+	openAPIController.GET("/users/:id", func(id int, verbose bool) user {
+		return user{}
+	}, resolver.Path("id", 1), resolver.Query("verbose", 2))
+
+	openAPIController.POST("/orders", func(o order) (order, error) {
+		return o, nil
+	})
+
+	openAPIController.ServeOpenAPI("/openapi", openapi.Info{Title: "Test", Version: "1.0.0"})
+}
+
+func Test_OpenAPI_Document(t *testing.T) {
+	doc := openAPIController.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+
+	getUser := doc.Paths["/users/{id}"].Get
+	assert.NotNil(t, getUser)
+	assert.Len(t, getUser.Parameters, 2)
+	assert.Equal(t, "id", getUser.Parameters[0].Name)
+	assert.Equal(t, "path", getUser.Parameters[0].In)
+	assert.True(t, getUser.Parameters[0].Required)
+	assert.Equal(t, "integer", getUser.Parameters[0].Schema.Type)
+	assert.Equal(t, "verbose", getUser.Parameters[1].Name)
+	assert.Equal(t, "query", getUser.Parameters[1].In)
+	assert.Equal(t, "#/components/schemas/user", getUser.Responses["200"].Content[gin.MIMEJSON].Schema.Ref)
+
+	postOrder := doc.Paths["/orders"].Post
+	assert.NotNil(t, postOrder)
+	assert.Equal(t, "extra", postOrder.Parameters[0].Name)
+	assert.Equal(t, "#/components/schemas/order", postOrder.RequestBody.Content[gin.MIMEJSON].Schema.Ref)
+	assert.NotNil(t, postOrder.Responses["default"])
+
+	orderSchema := doc.Components.Schemas["order"]
+	assert.Contains(t, orderSchema.Properties, "product")
+	assert.NotContains(t, orderSchema.Properties, "Extra")
+}
+
+func Test_OpenAPI_Serve(t *testing.T) {
+	res := openAPIController.Tester().GET("/openapi", nil)
+	assert.Equal(t, 200, res.Code)
+
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &doc))
+	assert.Equal(t, "3.1.0", doc["openapi"])
+
+	res = openAPIController.Tester().GET("/openapi?format=yaml", nil)
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "application/yaml", res.Header().Get("Content-Type"))
+	assert.Contains(t, res.Body.String(), "openapi: 3.1.0")
+}
+
+func statusText() string {
+	return "ok"
+}
+
+func Test_OpenAPI_UniqueOperationIds(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.GET("/a", statusText)
+	c.GET("/b", statusText)
+
+	doc := c.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	assert.Equal(t, "statusText", doc.Paths["/a"].Get.OperationID)
+	assert.Equal(t, "statusText_get_b", doc.Paths["/b"].Get.OperationID)
+}
+
+func Test_OpenAPI_GroupContentType(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	api := c.Group("/api")
+	api.ContentType = gin.MIMEJSON
+	api.GET("/posts", func() []blogPost {
+		return nil
+	})
+
+	doc := c.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	assert.Contains(t, doc.Paths["/api/posts"].Get.Responses["200"].Content, gin.MIMEJSON)
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type userID struct {
+	ginx.Path[int] `ginx:"id"`
+}
+
+type pageNumber struct {
+	ginx.Query[int] `ginx:"page"`
+}
+
+type apiToken struct {
+	ginx.Header[string] `ginx:"token"`
+}
+
+type userName string
+
+type sortOrder string
+
+var paramsController *ginx.Controller
+
+func init() {
+	paramsController = ginx.NewDefaultController(gin.New())
+
+	paramsController.GET("/users/:id/posts", func(token apiToken, page pageNumber, id userID) string {
+		return fmt.Sprintf("%d:%d:%s", id.Value, page.Value, token.Value)
+	})
+
+	paramsController.GET("/profiles/:name", func(order sortOrder, name userName) string {
+		return fmt.Sprintf("%s:%s", name, order)
+	}, resolver.PathFor[userName]("name"), resolver.QueryFor[sortOrder]("sort"))
+}
+
+func Test_Params_Wrappers(t *testing.T) {
+	res := paramsController.Tester().GET("/users/7/posts?page=2", map[string]string{"token": "secret"})
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "7:2:secret", res.Body.String())
+}
+
+func Test_Params_WrappersBindingError(t *testing.T) {
+	res := paramsController.Tester().GET("/users/abc/posts", nil)
+
+	assert.Equal(t, 400, res.Code)
+}
+
+type geoPoint struct {
+	Lat, Lng float64
+}
+
+func Test_Params_UnconvertibleWrapper(t *testing.T) {
+	c := ginx.NewDefaultController(gin.New())
+
+	err := c.GET("/points/:id", func(id struct {
+		ginx.Path[geoPoint] `ginx:"id"`
+	}) string {
+		return ""
+	})
+
+	assert.ErrorContains(t, err, "argument at position [1]")
+	assert.ErrorContains(t, err, "path variable 'id' can not be bound to tests.geoPoint")
+}
+
+func Test_Params_ByType(t *testing.T) {
+	res := paramsController.Tester().GET("/profiles/john?sort=desc", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "john:desc", res.Body.String())
+}
+
+func Test_Params_OpenAPI(t *testing.T) {
+	doc := paramsController.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	params := doc.Paths["/users/{id}/posts"].Get.Parameters
+	assert.Len(t, params, 3)
+	assert.Equal(t, "token", params[0].Name)
+	assert.Equal(t, "header", params[0].In)
+	assert.Equal(t, "page", params[1].Name)
+	assert.Equal(t, "integer", params[1].Schema.Type)
+	assert.Equal(t, "id", params[2].Name)
+}
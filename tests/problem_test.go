@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+var problemController *ginx.Controller
+
+// loggedTraceId is the trace identifier the error interceptor finds in the context of the last failed request.
+var loggedTraceId string
+
+func init() {
+	problemController = ginx.NewController(gin.New())
+	problemController.ContentType = gin.MIMEJSON
+	problemController.Use(ginx.ProblemDetails().TypeURI("https://example.com/problems/"))
+	problemController.Use(ginx.ErrorInterceptorFunc(func(e ginx.Error) {
+		loggedTraceId = e.Context().GetString("ginx_trace_id")
+	}))
+
+	problemController.GET("/users/:id", func(id int) (user, error) {
+		return user{}, ginx.NewHTTPError(http.StatusNotFound, "user not found").WithCode("user_not_found")
+	}, resolver.Path("id", 1))
+	problemController.GET("/posts", func(page int) string {
+		return "posts"
+	}, resolver.Query("page", 1).Validate("min=1"))
+	problemController.GET("/panic", func() {
+		panic(errors.New("boom"))
+	})
+}
+
+func Test_Problem_HTTPError(t *testing.T) {
+	res := problemController.Tester().GET("/users/1", map[string]string{"X-Request-ID": "req-1"})
+
+	assert.Equal(t, 404, res.Code)
+	assert.Equal(t, "application/problem+json; charset=utf-8", res.Header().Get("Content-Type"))
+
+	var problem map[string]any
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+	assert.Equal(t, "https://example.com/problems/user_not_found", problem["type"])
+	assert.Equal(t, "Not Found", problem["title"])
+	assert.Equal(t, float64(404), problem["status"])
+	assert.Equal(t, "user not found", problem["detail"])
+	assert.Equal(t, "/users/1", problem["instance"])
+	assert.Equal(t, "user_not_found", problem["code"])
+	assert.Equal(t, "req-1", problem["traceId"])
+}
+
+func Test_Problem_Validation(t *testing.T) {
+	res := problemController.Tester().GET("/posts?page=0", nil)
+
+	assert.Equal(t, 422, res.Code)
+
+	var problem map[string]any
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+	assert.Equal(t, "about:blank", problem["type"])
+	assert.Len(t, problem["errors"], 1)
+	assert.NotEmpty(t, problem["traceId"])
+	assert.Equal(t, problem["traceId"], res.Header().Get("X-Request-ID"))
+	assert.Equal(t, problem["traceId"], loggedTraceId)
+}
+
+func Test_Problem_Panic(t *testing.T) {
+	res := problemController.Tester().GET("/panic", nil)
+
+	assert.Equal(t, 500, res.Code)
+
+	var problem map[string]any
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+	assert.Equal(t, "Internal Server Error", problem["title"])
+	assert.NotContains(t, problem, "detail")
+}
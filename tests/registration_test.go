@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func Test_Registration_Succeeds(t *testing.T) {
+	c := ginx.NewController(gin.New())
+
+	assert.NoError(t, c.GET("/users/:id", func(id int) (user, error) {
+		return user{}, nil
+	}, resolver.Path("id", 1)))
+	assert.NoError(t, c.GET("/posts", func() ([]blogPost, int) {
+		return nil, 404
+	}))
+}
+
+func Test_Registration_MissingResolver(t *testing.T) {
+	c := ginx.NewController(gin.New())
+
+	err := c.GET("/users/:id", func(id int) user {
+		return user{}
+	})
+
+	assert.ErrorContains(t, err, "GET /users/:id: handler")
+	assert.ErrorContains(t, err, "resolver not found for argument at position [1] of type int")
+}
+
+func Test_Registration_UnsupportedReturnTypes(t *testing.T) {
+	c := ginx.NewController(gin.New())
+
+	assert.ErrorContains(t, c.GET("/a", func() (user, string) {
+		return user{}, ""
+	}), "unsupported return types (tests.user, string)")
+	assert.ErrorContains(t, c.GET("/b", func() (user, int, error) {
+		return user{}, 0, nil
+	}), "too many return values [3]")
+}
+
+func Test_Registration_MustPanics(t *testing.T) {
+	c := ginx.NewController(gin.New())
+
+	assert.Panics(t, func() {
+		c.MustGET("/users/:id", func(id int) {})
+	})
+	assert.NotPanics(t, func() {
+		c.MustGET("/users/:id", func(id int) {}, resolver.Path("id", 1))
+	})
+}
+
+func Test_Registration_UnsignedStatus(t *testing.T) {
+	c := ginx.NewController(gin.New())
+
+	assert.NoError(t, c.GET("/accepted", func() (string, uint) {
+		return "queued", 202
+	}))
+
+	res := c.Tester().GET("/accepted", nil)
+
+	assert.Equal(t, 202, res.Code)
+	assert.Equal(t, "queued", res.Body.String())
+}
+
+// overrideResolver resolves string arguments from the X-Override header if the request has it.
+type overrideResolver struct{}
+
+func (overrideResolver) Priority() int {
+	return 1000
+}
+
+func (overrideResolver) CanResolve(ctx *gin.Context, argumentType reflect.Type, _ int) bool {
+	return argumentType.Kind() == reflect.String && ctx.GetHeader("X-Override") != ""
+}
+
+func (overrideResolver) Resolve(ctx *gin.Context, _ reflect.Type) (reflect.Value, error) {
+	return reflect.ValueOf(ctx.GetHeader("X-Override")), nil
+}
+
+func Test_Registration_RequestDependentResolver(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.Use(overrideResolver{})
+
+	assert.NoError(t, c.GET("/users/:name", func(name string, id int) string {
+		return name
+	}, resolver.Path("name", 1), resolver.Query("id", 2)))
+
+	assert.Equal(t, "john", c.Tester().GET("/users/john", nil).Body.String())
+	assert.Equal(t, "jane", c.Tester().GET("/users/john", map[string]string{"X-Override": "jane"}).Body.String())
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+type upperRenderer struct{}
+
+func (upperRenderer) MediaTypes() []string {
+	return []string{"text/x-upper"}
+}
+
+func (upperRenderer) Render(w io.Writer, body any) error {
+	_, err := io.WriteString(w, strings.ToUpper(body.(blogPost).Title))
+	return err
+}
+
+var rendererController *ginx.Controller
+
+func init() {
+	rendererController = ginx.NewController(gin.New())
+	rendererController.Use(upperRenderer{})
+
+	post := func() blogPost {
+		return blogPost{Title: "First post", Content: "Hello, world"}
+	}
+
+	rendererController.GET("/posts/yaml", post, ginx.Produce("application/yaml"))
+	rendererController.GET("/posts/csv", func() []blogPost {
+		return []blogPost{{Title: "First post", Content: "Hello, world"}, {Title: "Monday", Content: "This is monday"}}
+	}, ginx.Produce("text/csv"))
+	rendererController.GET("/posts/vnd", post, ginx.Produce("application/vnd.blog+json"))
+	rendererController.GET("/posts/upper", post, ginx.Produce("text/x-upper"))
+}
+
+func Test_Renderer_YAML(t *testing.T) {
+	res := rendererController.Tester().GET("/posts/yaml", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "application/yaml; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.Equal(t, "title: First post\ncontent: Hello, world\n", res.Body.String())
+}
+
+func Test_Renderer_CSV(t *testing.T) {
+	res := rendererController.Tester().GET("/posts/csv", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "Title,Content\nFirst post,\"Hello, world\"\nMonday,This is monday\n", res.Body.String())
+}
+
+func Test_Renderer_StructuredSuffix(t *testing.T) {
+	res := rendererController.Tester().GET("/posts/vnd", nil)
+
+	assert.Equal(t, "application/vnd.blog+json; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.Equal(t, `{"title":"First post","content":"Hello, world"}`, res.Body.String())
+}
+
+func Test_Renderer_Custom(t *testing.T) {
+	res := rendererController.Tester().GET("/posts/upper", nil)
+
+	assert.Equal(t, "FIRST POST", res.Body.String())
+}
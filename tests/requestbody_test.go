@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/requestbody"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type yamlOrder struct {
+	requestbody.YAML
+	ID      int    `yaml:"id"`
+	Product string `yaml:"product"`
+}
+
+type formOrder struct {
+	requestbody.Form
+	ID      int    `form:"id"`
+	Product string `form:"product"`
+}
+
+type anyOrder struct {
+	requestbody.Any
+	ID      int    `json:"id" xml:"id" yaml:"id"`
+	Product string `json:"product" xml:"product" yaml:"product"`
+}
+
+var requestBodyController *ginx.Controller
+
+func init() {
+	requestBodyController = ginx.NewController(gin.New())
+	requestBodyController.Use(resolver.Struct())
+
+	requestBodyController.POST("/orders/yaml", func(o yamlOrder) string {
+		return fmt.Sprintf("<%s:%d>", o.Product, o.ID)
+	})
+	requestBodyController.POST("/orders/form", func(o formOrder) string {
+		return fmt.Sprintf("<%s:%d>", o.Product, o.ID)
+	})
+	requestBodyController.POST("/orders/any", func(o anyOrder) string {
+		return fmt.Sprintf("<%s:%d>", o.Product, o.ID)
+	})
+}
+
+func postWithContentType(url, contentType, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", url, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return requestBodyController.Tester().Do(req)
+}
+
+func Test_RequestBody_YAML(t *testing.T) {
+	res := postWithContentType("/orders/yaml", "application/yaml", "id: 7\nproduct: tea\n")
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "<tea:7>", res.Body.String())
+}
+
+func Test_RequestBody_Form(t *testing.T) {
+	res := postWithContentType("/orders/form", "application/x-www-form-urlencoded", "id=8&product=coffee")
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "<coffee:8>", res.Body.String())
+}
+
+func Test_RequestBody_ByContentType(t *testing.T) {
+	res := postWithContentType("/orders/any", "application/vnd.order+json", `{"id":9,"product":"milk"}`)
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "<milk:9>", res.Body.String())
+
+	res = postWithContentType("/orders/any", "text/yaml", "id: 10\nproduct: water\n")
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "<water:10>", res.Body.String())
+}
+
+func Test_RequestBody_UnsupportedMediaType(t *testing.T) {
+	res := postWithContentType("/orders/any", "application/octet-stream", "...")
+
+	assert.Equal(t, 415, res.Code)
+	assert.JSONEq(t, `{"code":"unsupported_media_type","message":"unsupported media type 'application/octet-stream'"}`, res.Body.String())
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type listRequest struct {
+	Token  string   `ginx:"header=X-Token,required"`
+	Tenant string   `ginx:"query=tenant,required"`
+	Limit  int      `ginx:"query=limit,default=20"`
+	Tags   []string `ginx:"query=tag,default=new|hot,sep=|"`
+	Cursor *string  `ginx:"query=cursor"`
+}
+
+type missingResponse struct {
+	Code    string                `json:"code"`
+	Details []ginx.MissingDetails `json:"details"`
+}
+
+var requiredController *ginx.Controller
+
+func init() {
+	requiredController = ginx.NewController(gin.New())
+	requiredController.ContentType = gin.MIMEJSON
+	requiredController.Use(resolver.Struct())
+
+	requiredController.GET("/posts", func(page int, size int, sort string) string {
+		return fmt.Sprintf("%d:%d:%s", page, size, sort)
+	}, resolver.Query("page", 1).Required(), resolver.Query("size", 2).Default(10), resolver.Query("sort", 3).Default("date"))
+
+	requiredController.GET("/feed", func(page *int, query listRequest) string {
+		cursor := "nil"
+		if query.Cursor != nil {
+			cursor = *query.Cursor
+		}
+		return fmt.Sprintf("%d:%s:%s:%d:%v:%s", *page, query.Token, query.Tenant, query.Limit, query.Tags, cursor)
+	}, resolver.Query("page", 1).Default(1))
+
+	requiredController.GET("/items", func(item int, query listRequest) string {
+		return "ok"
+	}, resolver.Query("item", 1).Required())
+}
+
+func Test_Required_Present(t *testing.T) {
+	res := requiredController.Tester().GET("/posts?page=2", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "2:10:date", res.Body.String())
+}
+
+func Test_Required_Missing(t *testing.T) {
+	res := requiredController.Tester().GET("/posts", nil)
+
+	assert.Equal(t, 400, res.Code)
+
+	var body missingResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(t, "missing_variables", body.Code)
+	assert.Equal(t, []ginx.MissingDetails{{Scope: "query", Variable: "page"}}, body.Details)
+}
+
+func Test_Required_StructTagsAndDefaults(t *testing.T) {
+	res := requiredController.Tester().GET("/feed?tenant=acme&cursor=", map[string]string{"X-Token": "secret"})
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "1:secret:acme:20:[new hot]:", res.Body.String())
+}
+
+func Test_Required_AllMissingListed(t *testing.T) {
+	res := requiredController.Tester().GET("/items", nil)
+
+	assert.Equal(t, 400, res.Code)
+
+	var body missingResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(t, []ginx.MissingDetails{
+		{Scope: "query", Variable: "item"},
+		{Scope: "header", Variable: "X-Token"},
+		{Scope: "query", Variable: "tenant"},
+	}, body.Details)
+}
+
+type badDefaultRequest struct {
+	Limit int `ginx:"query=limit,default=many"`
+}
+
+func Test_Required_InvalidDefaults(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.Use(resolver.Struct())
+
+	err := c.GET("/a", func(n int) string { return "" }, resolver.Query("n", 1).Default("abc"))
+	assert.ErrorContains(t, err, "argument at position [1] of type int: invalid default of query variable 'n'")
+
+	err = c.GET("/b", func(n int) string { return "" }, resolver.Query("n", 1).Default(3.7))
+	assert.ErrorContains(t, err, "default value 3.7 of float64 is not convertible to int")
+
+	err = c.GET("/c", func(n uint8) string { return "" }, resolver.Query("n", 1).Default(-1))
+	assert.ErrorContains(t, err, "default value -1 of int is not convertible to uint8")
+
+	err = c.GET("/d", func(query badDefaultRequest) string { return "" })
+	assert.ErrorContains(t, err, "field Limit: invalid default of query variable 'limit'")
+
+	assert.NoError(t, c.GET("/e", func(n int8, ratio float32) string { return "" }, resolver.Query("n", 1).Default(3.0), resolver.Query("ratio", 2).Default(0.1)))
+}
+
+func Test_Required_OpenAPI(t *testing.T) {
+	doc := requiredController.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	posts := doc.Paths["/posts"].Get.Parameters
+	assert.True(t, posts[0].Required)
+	assert.False(t, posts[1].Required)
+	assert.Equal(t, 10, posts[1].Schema.Default)
+
+	feed := doc.Paths["/feed"].Get.Parameters
+	assert.Equal(t, "X-Token", feed[1].Name)
+	assert.True(t, feed[1].Required)
+	assert.Equal(t, "20", feed[3].Schema.Default)
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type createdUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+var responseController *ginx.Controller
+
+func init() {
+	responseController = ginx.NewController(gin.New())
+	responseController.ContentType = gin.MIMEJSON
+
+	responseController.POST("/users", func() ginx.Response {
+		return ginx.Created("/users/1", &createdUser{ID: 1, Name: "John"})
+	})
+
+	responseController.DELETE("/users/1", func() ginx.Response {
+		return ginx.NoContent()
+	})
+
+	responseController.GET("/users/1", func() (ginx.Response, error) {
+		res := ginx.NewResponse(http.StatusOK)
+		res.SetBody(&createdUser{ID: 1, Name: "John"})
+		res.Header().Set("Cache-Control", "max-age=60")
+		res.Header().Add("X-Feature", "a")
+		res.Header().Add("X-Feature", "b")
+		res.SetCookie(&http.Cookie{Name: "last_seen", Value: "1", Path: "/", HttpOnly: true})
+		return res, nil
+	})
+
+	responseController.GET("/error", func() (string, error) {
+		return "", ginx.NewHTTPError(http.StatusTooManyRequests, "slow down")
+	})
+
+	responseController.Use(ginx.ErrorInterceptorFunc(func(e ginx.Error) {
+		e.Response().Header().Set("Retry-After", "30")
+	}))
+}
+
+func Test_Response_Created(t *testing.T) {
+	res := responseController.Tester().POST("/users", nil)
+
+	assert.Equal(t, 201, res.Code)
+	assert.Equal(t, "/users/1", res.Header().Get("Location"))
+	assert.JSONEq(t, `{"id":1,"name":"John"}`, res.Body.String())
+}
+
+func Test_Response_NoContent(t *testing.T) {
+	res := responseController.Tester().DELETE("/users/1")
+
+	assert.Equal(t, 204, res.Code)
+	assert.Empty(t, res.Body.String())
+}
+
+func Test_Response_HeadersAndCookies(t *testing.T) {
+	res := responseController.Tester().GET("/users/1", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "max-age=60", res.Header().Get("Cache-Control"))
+	assert.Equal(t, []string{"a", "b"}, res.Header().Values("X-Feature"))
+	assert.Equal(t, "last_seen=1; Path=/; HttpOnly", res.Header().Get("Set-Cookie"))
+}
+
+func Test_Response_ErrorHeaders(t *testing.T) {
+	res := responseController.Tester().GET("/error", nil)
+
+	assert.Equal(t, 429, res.Code)
+	assert.Equal(t, "30", res.Header().Get("Retry-After"))
+}
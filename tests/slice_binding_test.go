@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type searchRequest struct {
+	Tags   []string          `ginx:"query=tag"`
+	IDs    []int             `ginx:"query=ids,sep=|"`
+	Point  [2]float64        `ginx:"query=point"`
+	Accept []string          `ginx:"header=X-Accept"`
+	Filter map[string]string `ginx:"query=filter"`
+}
+
+var sliceBindingController *ginx.Controller
+
+func init() {
+	sliceBindingController = ginx.NewController(gin.New())
+	sliceBindingController.Use(resolver.Struct())
+
+	sliceBindingController.GET("/posts", func(tags []string, ids []int) string {
+		return fmt.Sprintf("%v:%v", tags, ids)
+	}, resolver.Query("tag", 1), resolver.Query("ids", 2))
+
+	sliceBindingController.GET("/raw", func(tags []string) string {
+		return fmt.Sprintf("%q", tags)
+	}, resolver.Query("tag", 1).Separator(""))
+
+	sliceBindingController.GET("/point", func(point [2]int) string {
+		return fmt.Sprintf("%v", point)
+	}, resolver.Query("p", 1).Separator("|"))
+
+	sliceBindingController.GET("/languages", func(languages []string) string {
+		return strings.Join(languages, "|")
+	}, resolver.Header("X-Language", 1))
+
+	sliceBindingController.GET("/filter", func(filter map[string]int) string {
+		keys := make([]string, 0, len(filter))
+		for k, v := range filter {
+			keys = append(keys, fmt.Sprintf("%s=%d", k, v))
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ",")
+	}, resolver.Query("filter", 1))
+
+	sliceBindingController.GET("/search", func(s searchRequest) string {
+		return fmt.Sprintf("%v %v %v %v %s", s.Tags, s.IDs, s.Point, s.Accept, s.Filter["name"])
+	})
+}
+
+func Test_SliceBinding_RepeatedAndCommaSeparated(t *testing.T) {
+	res := sliceBindingController.Tester().GET("/posts?tag=go&tag=web&ids=1,2&ids=3", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "[go web]:[1 2 3]", res.Body.String())
+}
+
+func Test_SliceBinding_Absent(t *testing.T) {
+	res := sliceBindingController.Tester().GET("/posts", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "[]:[]", res.Body.String())
+}
+
+func Test_SliceBinding_ElementError(t *testing.T) {
+	res := sliceBindingController.Tester().GET("/posts?ids=1,x", nil)
+
+	assert.Equal(t, 400, res.Code)
+}
+
+func Test_SliceBinding_NoSeparator(t *testing.T) {
+	res := sliceBindingController.Tester().GET("/raw?tag=a,b", nil)
+
+	assert.Equal(t, `["a,b"]`, res.Body.String())
+}
+
+func Test_SliceBinding_Array(t *testing.T) {
+	assert.Equal(t, "[1 2]", sliceBindingController.Tester().GET("/point?p=1|2", nil).Body.String())
+	assert.Equal(t, 400, sliceBindingController.Tester().GET("/point?p=1|2|3", nil).Code)
+}
+
+func Test_SliceBinding_RepeatedHeaders(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/languages", nil)
+	req.Header.Add("X-Language", "en, de")
+	req.Header.Add("X-Language", "fr")
+
+	res := sliceBindingController.Tester().Do(req)
+
+	assert.Equal(t, "en|de|fr", res.Body.String())
+}
+
+func Test_SliceBinding_Map(t *testing.T) {
+	res := sliceBindingController.Tester().GET("/filter?filter[age]=30&filter[rank]=2", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "age=30,rank=2", res.Body.String())
+}
+
+func Test_SliceBinding_StructTags(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/search?tag=a,b&ids=1|2&point=1.5,2.5&filter[name]=john", nil)
+	req.Header.Add("X-Accept", "json")
+	req.Header.Add("X-Accept", "xml")
+
+	res := sliceBindingController.Tester().Do(req)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "[a b] [1 2] [1.5 2.5] [json xml] john", res.Body.String())
+}
+
+func Test_SliceBinding_OpenAPI(t *testing.T) {
+	doc := sliceBindingController.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	posts := doc.Paths["/posts"].Get.Parameters
+	assert.Equal(t, "array", posts[0].Schema.Type)
+
+	filter := doc.Paths["/filter"].Get.Parameters[0]
+	assert.Equal(t, "deepObject", filter.Style)
+	assert.True(t, filter.Explode)
+}
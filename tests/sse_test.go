@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type tick struct {
+	N int `json:"n"`
+}
+
+var sseController *ginx.Controller
+
+func init() {
+	sseController = ginx.NewDefaultController(gin.New())
+	sseController.ContentType = gin.MIMEJSON
+
+	sseController.GET("/events", func(lastID ginx.LastEventID) <-chan ginx.Event {
+		events := make(chan ginx.Event, 2)
+		events <- ginx.Event{ID: "1", Event: "greeting", Data: "hello " + string(lastID), Retry: 3 * time.Second}
+		events <- ginx.Event{ID: "2", Data: tick{N: 2}}
+		close(events)
+		return events
+	})
+
+	sseController.GET("/slow", func() <-chan *ginx.Event {
+		events := make(chan *ginx.Event)
+		go func() {
+			defer close(events)
+			time.Sleep(50 * time.Millisecond)
+			events <- &ginx.Event{Data: "done"}
+		}()
+		return events
+	}, ginx.Heartbeat(5*time.Millisecond))
+
+	sseController.GET("/endless", func(ctx context.Context) <-chan ginx.Event {
+		return make(chan ginx.Event)
+	})
+}
+
+func Test_SSE_Events(t *testing.T) {
+	res := sseController.Tester().GET("/events", map[string]string{"Accept": ginx.MIMEEventStream, "Last-Event-ID": "0"})
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, ginx.MIMEEventStream, res.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", res.Header().Get("Cache-Control"))
+	assert.Equal(t, "id:1\nevent:greeting\nretry:3000\ndata:hello 0\n\nid:2\ndata:{\"n\":2}\n\n", res.Body.String())
+}
+
+func Test_SSE_Heartbeat(t *testing.T) {
+	res := sseController.Tester().GET("/slow", nil)
+
+	assert.True(t, strings.HasPrefix(res.Body.String(), ":\n\n"))
+	assert.True(t, strings.HasSuffix(res.Body.String(), "data:done\n\n"))
+}
+
+func Test_SSE_ClientDisconnect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/endless", nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sseController.Tester().Do(req)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("event stream did not stop after the client disconnected")
+	}
+}
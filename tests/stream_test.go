@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/renderer"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type exportRow struct {
+	ID   int    `json:"id" csv:"id"`
+	Name string `json:"name" csv:"name"`
+}
+
+func exportRows(n int) func(yield func(exportRow) bool) {
+	return func(yield func(exportRow) bool) {
+		for i := 1; i <= n; i++ {
+			if !yield(exportRow{ID: i, Name: strings.Repeat("x", i)}) {
+				return
+			}
+		}
+	}
+}
+
+var streamRouter *gin.Engine
+var streamController *ginx.Controller
+
+func init() {
+	streamRouter = gin.New()
+	streamController = ginx.NewController(streamRouter)
+	streamController.Use(renderer.CSV())
+
+	streamController.GET("/reader", func() io.Reader {
+		return strings.NewReader("raw data")
+	}, ginx.Produce("application/octet-stream"))
+
+	streamController.GET("/channel", func() <-chan exportRow {
+		ch := make(chan exportRow)
+		go func() {
+			defer close(ch)
+			for i := 1; i <= 3; i++ {
+				ch <- exportRow{ID: i, Name: "row"}
+			}
+		}()
+		return ch
+	})
+
+	streamController.GET("/empty", func() <-chan exportRow {
+		ch := make(chan exportRow)
+		close(ch)
+		return ch
+	})
+
+	streamController.GET("/iterator", func() func(yield func(exportRow) bool) {
+		return exportRows(2)
+	}, ginx.Produces(gin.MIMEJSON, ginx.MIMENDJSON, "text/csv"))
+
+	streamController.GET("/failing", func() <-chan any {
+		ch := make(chan any, 3)
+		ch <- exportRow{ID: 1}
+		ch <- errors.New("database is gone")
+		ch <- exportRow{ID: 2}
+		close(ch)
+		return ch
+	}, ginx.Produce(ginx.MIMENDJSON))
+
+	streamController.GET("/failing-array", func() func(yield func(any) bool) {
+		return func(yield func(any) bool) {
+			_ = yield(exportRow{ID: 1}) && yield(func() {})
+		}
+	})
+}
+
+// getStream requests the streamed response by a real server, so an aborted response is seen as by clients.
+func getStream(t *testing.T, path string) (string, error) {
+	server := httptest.NewServer(streamRouter)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + path)
+	if !assert.NoError(t, err) {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	return string(body), err
+}
+
+func Test_Stream_Reader(t *testing.T) {
+	res := streamController.Tester().GET("/reader", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "application/octet-stream", res.Header().Get("Content-Type"))
+	assert.Equal(t, "raw data", res.Body.String())
+}
+
+func Test_Stream_ChannelAsJSONArray(t *testing.T) {
+	res := streamController.Tester().GET("/channel", nil)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, gin.MIMEJSON, res.Header().Get("Content-Type"))
+	assert.JSONEq(t, `[{"id":1,"name":"row"},{"id":2,"name":"row"},{"id":3,"name":"row"}]`, res.Body.String())
+	assert.True(t, res.Flushed)
+}
+
+func Test_Stream_EmptyChannel(t *testing.T) {
+	res := streamController.Tester().GET("/empty", nil)
+
+	assert.Equal(t, "[]", res.Body.String())
+}
+
+func Test_Stream_IteratorNDJSON(t *testing.T) {
+	res := streamController.Tester().GET("/iterator", map[string]string{"Accept": ginx.MIMENDJSON})
+
+	assert.Equal(t, ginx.MIMENDJSON, res.Header().Get("Content-Type"))
+	assert.Equal(t, "{\"id\":1,\"name\":\"x\"}\n{\"id\":2,\"name\":\"xx\"}\n", res.Body.String())
+}
+
+func Test_Stream_IteratorCSV(t *testing.T) {
+	res := streamController.Tester().GET("/iterator", map[string]string{"Accept": "text/csv"})
+
+	assert.Equal(t, "id,name\n1,x\n2,xx\n", res.Body.String())
+}
+
+func Test_Stream_ErrorElementAborts(t *testing.T) {
+	body, err := getStream(t, "/failing")
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, "{\"id\":1,\"name\":\"\"}\n", body)
+}
+
+func Test_Stream_EncodingFailureLeavesArrayOpen(t *testing.T) {
+	body, err := getStream(t, "/failing-array")
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, `[{"id":1,"name":""}`, body)
+}
+
+func Test_Stream_ClientDisconnect(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	produced := make(chan int, 100)
+	c.GET("/infinite", func() func(yield func(int) bool) {
+		return func(yield func(int) bool) {
+			for i := 0; ; i++ {
+				produced <- i
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/infinite", nil)
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Tester().Do(req)
+	}()
+
+	// Drain produced values, the iterator must stop after the cancellation.
+	go func() {
+		for range produced {
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("streaming did not stop after the client disconnected")
+	}
+}
+
+func Test_Stream_OpenAPI(t *testing.T) {
+	doc := streamController.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	schema := doc.Paths["/iterator"].Get.Responses["200"].Content[gin.MIMEJSON].Schema
+	assert.Equal(t, "array", schema.Type)
+	assert.Equal(t, "#/components/schemas/exportRow", schema.Items.Ref)
+}
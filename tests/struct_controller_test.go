@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type userRepo struct {
+	users map[string]user
+}
+
+// userController routes are discovered by the method naming convention.
+type userController struct {
+	repo *userRepo
+}
+
+func (uc *userController) GetUsers() []user {
+	var users []user
+	for _, u := range uc.repo.users {
+		users = append(users, u)
+	}
+	return users
+}
+
+func (uc *userController) PostUserAccount() string {
+	return "created"
+}
+
+func (uc *userController) helper() {}
+
+// orderController declares its routes explicitly.
+type orderController struct{}
+
+func (oc *orderController) Routes() []ginx.Route {
+	return []ginx.Route{
+		{Method: "GET", Path: "/orders/:id", Handler: "FindOrder", Options: []ginx.HandlerOption{resolver.Path("id", 1)}},
+		{Method: "DELETE", Path: "/orders/:id", Handler: oc.RemoveOrder, Options: []ginx.HandlerOption{resolver.Path("id", 1)}},
+	}
+}
+
+func (oc *orderController) FindOrder(id int) order {
+	return order{ID: id}
+}
+
+func (oc *orderController) RemoveOrder(id int) int {
+	return 204
+}
+
+func Test_StructController_NamingConvention(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.ContentType = gin.MIMEJSON
+
+	err := c.Group("/api").Register(&userController{repo: &userRepo{users: map[string]user{"root": {Login: "root"}}}})
+	assert.NoError(t, err)
+
+	res := c.Tester().GET("/api/users", nil)
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, `[{"login":"root"}]`, res.Body.String())
+
+	res = c.Tester().POST("/api/user-account", nil)
+	assert.Equal(t, 200, res.Code)
+}
+
+func Test_StructController_Routes(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.ContentType = gin.MIMEJSON
+
+	assert.NoError(t, c.Register(&orderController{}))
+
+	res := c.Tester().GET("/orders/5", nil)
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, `{"id":5,"name":"","product":""}`, res.Body.String())
+
+	res = c.Tester().DELETE("/orders/5")
+	assert.Equal(t, 204, res.Code)
+}
+
+type invalidController struct{}
+
+func (invalidController) Routes() []ginx.Route {
+	return []ginx.Route{{Method: "TRACE", Path: "/", Handler: func() {}}}
+}
+
+type unresolvableController struct{}
+
+func (unresolvableController) GetThing(id int) string {
+	return "thing"
+}
+
+func Test_StructController_Errors(t *testing.T) {
+	c := ginx.NewController(gin.New())
+
+	assert.EqualError(t, c.Register(invalidController{}), "unknown method 'TRACE'")
+	assert.Panics(t, func() {
+		c.MustRegister(unresolvableController{})
+	})
+}
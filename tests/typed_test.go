@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/requestbody"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+type createOrderRequest struct {
+	requestbody.JSON
+	Channel string `ginx:"query=channel" json:"-"`
+	Product string `json:"product" validate:"required"`
+}
+
+type createdOrder struct {
+	Product string `json:"product"`
+	Channel string `json:"channel"`
+}
+
+var errOutOfStock = errors.New("out of stock")
+
+var typedController *ginx.Controller
+
+func init() {
+	typedController = ginx.NewController(gin.New())
+	typedController.ContentType = gin.MIMEJSON
+	typedController.Use(ginx.ErrorStatus(errOutOfStock, 409))
+
+	ginx.MustHandle(typedController, "POST", "/orders", func(ctx context.Context, req createOrderRequest) (*createdOrder, error) {
+		if req.Product == "gold" {
+			return nil, errOutOfStock
+		}
+		return &createdOrder{Product: req.Product, Channel: req.Channel}, nil
+	})
+}
+
+func Test_Handle_Typed(t *testing.T) {
+	res := typedController.Tester().POSTJson("/orders?channel=web", map[string]any{"product": "apple"})
+
+	assert.Equal(t, 200, res.Code)
+	assert.JSONEq(t, `{"product":"apple","channel":"web"}`, res.Body.String())
+}
+
+func Test_Handle_TypedError(t *testing.T) {
+	res := typedController.Tester().POSTJson("/orders", map[string]any{"product": "gold"})
+
+	assert.Equal(t, 409, res.Code)
+}
+
+func Test_Handle_TypedValidation(t *testing.T) {
+	res := typedController.Tester().POSTJson("/orders", map[string]any{})
+
+	assert.Equal(t, 422, res.Code)
+}
+
+func Test_Handle_TypedResponse(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	ginx.MustHandle(c, "DELETE", "/orders/:id", func(ctx context.Context, _ struct{}) (ginx.Response, error) {
+		return ginx.NewResponse(204), nil
+	})
+
+	res := c.Tester().DELETE("/orders/1")
+
+	assert.Equal(t, 204, res.Code)
+}
+
+func Test_Handle_UnsupportedRequestType(t *testing.T) {
+	c := ginx.NewController(gin.New())
+
+	err := ginx.Handle(c, "GET", "/orders", func(ctx context.Context, id int) (string, error) {
+		return "", nil
+	})
+
+	assert.Error(t, err)
+}
+
+func Test_Handle_OpenAPI(t *testing.T) {
+	doc := typedController.OpenAPI(openapi.Info{Title: "Test", Version: "1.0.0"})
+
+	op := doc.Paths["/orders"].Post
+	assert.NotNil(t, op.RequestBody)
+	assert.Equal(t, "channel", op.Parameters[0].Name)
+	assert.Contains(t, op.Responses, "default")
+}
+
+// tenantResolver is a request dependent resolver taking precedence over the built-in ones.
+type tenantResolver struct{}
+
+type tenant string
+
+func (tenantResolver) Priority() int {
+	return 1000
+}
+
+func (tenantResolver) CanResolve(ctx *gin.Context, argumentType reflect.Type, _ int) bool {
+	return argumentType == reflect.TypeOf(tenant("")) && ctx.GetHeader("X-Tenant") != ""
+}
+
+func (tenantResolver) Resolve(ctx *gin.Context, _ reflect.Type) (reflect.Value, error) {
+	return reflect.ValueOf(tenant(ctx.GetHeader("X-Tenant"))), nil
+}
+
+func Test_Handle_RequestDependentResolver(t *testing.T) {
+	c := ginx.NewController(gin.New())
+	c.ContentType = gin.MIMEJSON
+	c.Use(tenantResolver{})
+	ginx.MustHandle(c, "POST", "/tenant-orders", func(ctx context.Context, req createOrderRequest) (*createdOrder, error) {
+		return &createdOrder{Product: req.Product}, nil
+	})
+
+	res := c.Tester().POSTJson("/tenant-orders", map[string]any{"product": "apple"})
+
+	assert.Equal(t, 200, res.Code)
+	assert.JSONEq(t, `{"product":"apple","channel":""}`, res.Body.String())
+}
+
+func Test_Handle_RejectsResolverOptions(t *testing.T) {
+	c := ginx.NewController(gin.New())
+
+	err := ginx.Handle(c, "GET", "/orders/:id", func(ctx context.Context, _ struct{}) (string, error) {
+		return "", nil
+	}, resolver.Path("id", 1))
+
+	assert.ErrorContains(t, err, "does not accept argument resolvers")
+}
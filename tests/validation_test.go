@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/paveldanilin/ginx"
+	"github.com/paveldanilin/ginx/requestbody"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type validatedOrder struct {
+	requestbody.JSON
+	Channel string `ginx:"query=channel" json:"-" validate:"oneof=web mobile"`
+	ID      int    `json:"id" validate:"required"`
+	SKU     string `json:"sku" validate:"sku"`
+}
+
+type validationResponse struct {
+	Message string            `json:"message"`
+	Errors  []ginx.FieldError `json:"errors"`
+}
+
+var validationController *ginx.Controller
+
+func init() {
+	validationController = ginx.NewController(gin.New())
+	validationController.ContentType = gin.MIMEJSON
+	validationController.Use(resolver.Struct())
+	validationController.Use(ginx.Validation("sku", func(fl validator.FieldLevel) bool {
+		return strings.HasPrefix(fl.Field().String(), "SKU-")
+	}))
+
+	validationController.POST("/orders", func(o validatedOrder) string {
+		return "created"
+	})
+	validationController.GET("/posts", func(page int) string {
+		return "posts"
+	}, resolver.Query("page", 1).Validate("min=1"))
+}
+
+func Test_Validation_Struct(t *testing.T) {
+	res := validationController.Tester().POSTJson("/orders?channel=web", map[string]any{"id": 1, "sku": "SKU-1"})
+	assert.Equal(t, 200, res.Code)
+
+	res = validationController.Tester().POSTJson("/orders?channel=fax", map[string]any{"sku": "1"})
+	assert.Equal(t, 422, res.Code)
+
+	var body validationResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Len(t, body.Errors, 3)
+	assert.Equal(t, "channel", body.Errors[0].Field)
+	assert.Equal(t, "oneof", body.Errors[0].Rule)
+	assert.Equal(t, "id", body.Errors[1].Field)
+	assert.Equal(t, "required", body.Errors[1].Rule)
+	assert.Equal(t, "sku", body.Errors[2].Field)
+	assert.Equal(t, "sku", body.Errors[2].Rule)
+}
+
+func Test_Validation_Value(t *testing.T) {
+	res := validationController.Tester().GET("/posts?page=1", nil)
+	assert.Equal(t, 200, res.Code)
+
+	res = validationController.Tester().GET("/posts?page=0", nil)
+	assert.Equal(t, 422, res.Code)
+
+	var body validationResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(t, "page", body.Errors[0].Field)
+	assert.Equal(t, "min", body.Errors[0].Rule)
+	assert.Equal(t, "1", body.Errors[0].Param)
+}
+
+func Test_Validation_MalformedRule(t *testing.T) {
+	c := ginx.NewController(gin.New())
+
+	err := c.GET("/posts", func(page int) string {
+		return "posts"
+	}, resolver.Query("page", 1).Validate("mni=1"))
+
+	assert.ErrorContains(t, err, "invalid validation rule 'mni=1' of argument at position [1]")
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type chatRoom struct {
+	ginx.Path[string] `ginx:"room"`
+}
+
+type chatMessage struct {
+	User string `json:"user" xml:"user"`
+	Text string `json:"text" xml:"text"`
+}
+
+type chatPrefix struct {
+	Value string
+}
+
+var wsController *ginx.Controller
+
+// wsServer serves wsController over the network, WebSocket connections can not be tested by a recorder.
+var wsServer *httptest.Server
+
+func init() {
+	r := gin.New()
+	wsController = ginx.NewDefaultController(r)
+	wsController.Use(ginx.Provide(&chatPrefix{Value: "#"}))
+
+	wsController.MustWS("/ws/chat/:room", func(conn *ginx.Conn, room chatRoom, prefix *chatPrefix) error {
+		for {
+			var msg chatMessage
+			if err := conn.Receive(&msg); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			msg.Text = prefix.Value + room.Value + ": " + msg.Text
+			if err := conn.Send(msg); err != nil {
+				return err
+			}
+		}
+	})
+
+	wsController.MustWS("/ws/xml", func(conn *ginx.Conn) {
+		_ = conn.Send(chatMessage{User: "bot", Text: "hi"})
+	}, ginx.ProduceXML())
+
+	wsController.MustWS("/ws/strict", func(conn *ginx.Conn) {
+		_ = conn.Send("ok")
+	}, ginx.CheckOrigin(func(r *http.Request) bool {
+		return r.Header.Get("Origin") == "https://chat.example.com"
+	}))
+
+	wsServer = httptest.NewServer(r)
+}
+
+func dialWS(path, origin string) (*websocket.Conn, error) {
+	return websocket.Dial("ws"+strings.TrimPrefix(wsServer.URL, "http")+path, "", origin)
+}
+
+func Test_WS_Echo(t *testing.T) {
+	ws, err := dialWS("/ws/chat/general", wsServer.URL)
+	assert.Nil(t, err)
+	defer ws.Close()
+
+	assert.Nil(t, websocket.JSON.Send(ws, chatMessage{User: "bob", Text: "hello"}))
+
+	var reply chatMessage
+	assert.Nil(t, websocket.JSON.Receive(ws, &reply))
+	assert.Equal(t, chatMessage{User: "bob", Text: "#general: hello"}, reply)
+}
+
+func Test_WS_ProduceXML(t *testing.T) {
+	ws, err := dialWS("/ws/xml", wsServer.URL)
+	assert.Nil(t, err)
+	defer ws.Close()
+
+	var reply string
+	assert.Nil(t, websocket.Message.Receive(ws, &reply))
+	assert.Equal(t, "<chatMessage><user>bot</user><text>hi</text></chatMessage>", reply)
+}
+
+func Test_WS_CheckOrigin(t *testing.T) {
+	_, err := dialWS("/ws/strict", "https://evil.example.com")
+	assert.NotNil(t, err)
+
+	ws, err := dialWS("/ws/strict", "https://chat.example.com")
+	assert.Nil(t, err)
+	defer ws.Close()
+
+	var reply string
+	assert.Nil(t, websocket.Message.Receive(ws, &reply))
+	assert.Equal(t, "ok", reply)
+}
+
+func Test_WS_CrossOriginRejectedByDefault(t *testing.T) {
+	_, err := dialWS("/ws/chat/general", "https://evil.example.com")
+	assert.NotNil(t, err)
+}
+
+func Test_WS_NotUpgradeRequest(t *testing.T) {
+	res := wsController.Tester().GET("/ws/xml", nil)
+
+	assert.Equal(t, 400, res.Code)
+}
+
+func Test_WS_RegistrationErrors(t *testing.T) {
+	c := ginx.NewDefaultController(gin.New())
+
+	err := c.WS("/ws/no-conn", func() {})
+	assert.ErrorContains(t, err, "websocket handler must take *ginx.Conn argument")
+
+	err = c.WS("/ws/value", func(conn *ginx.Conn) string { return "" })
+	assert.ErrorContains(t, err, "unsupported return types of websocket handler")
+}
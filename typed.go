@@ -0,0 +1,87 @@
+package ginx
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx/resolver"
+	"github.com/paveldanilin/ginx/slices"
+	"reflect"
+)
+
+// TypedHandlerFunc represents a request handler with the request and response types known at compile time.
+type TypedHandlerFunc[Req, Resp any] func(ctx context.Context, req Req) (Resp, error)
+
+// Handle registers a typed request handler.
+// Req is populated the same way as a struct argument (see resolver.Struct) and validated,
+// Resp is rendered according to the negotiated content type.
+// The handler is called directly, there is no reflect.Value.Call on the request path.
+//
+//	type createOrder struct {
+//		requestbody.JSON
+//		Product string `json:"product" validate:"required"`
+//	}
+//
+//	ginx.Handle(controller, "POST", "/orders", func(ctx context.Context, req createOrder) (*Order, error) {
+//		return orders.Create(ctx, req.Product)
+//	}, ginx.ProduceJSON())
+func Handle[Req, Resp any](c *Controller, method, path string, fn TypedHandlerFunc[Req, Resp], opts ...HandlerOption) error {
+	h, err := c.newHandler(method, path, fn)
+	if err != nil {
+		return err
+	}
+
+	// The request is bound as a whole, handler resolvers would have no argument to resolve.
+	if len(handlerOptions(opts).withoutResolvers()) != len(opts) {
+		return fmt.Errorf("%s %s: typed handler %s does not accept argument resolvers", h.method, h.path, h.name)
+	}
+
+	// Resolvers registered on the controller take precedence, i.e. a configured resolver.Struct.
+	// The context and struct resolvers are always available, so a bare controller can serve typed handlers.
+	resolvers := slices.Join(c.allArgumentResolvers(), []ArgumentResolver{resolver.Context(), resolver.Struct()})
+	h.init(resolvers, opts...)
+
+	reqType := reflect.TypeOf((*Req)(nil)).Elem()
+
+	h.invoke = func(ctx *gin.Context) (Response, error) {
+		reqResolver := h.argumentResolver(ctx, 1)
+		if reqResolver == nil {
+			return nil, fmt.Errorf("resolver not found for argument at position [2]")
+		}
+
+		arg, err := reqResolver.Resolve(ctx, reqType)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.validateArguments([]reflect.Value{arg}, []ArgumentResolver{reqResolver}); err != nil {
+			return nil, err
+		}
+
+		resp, err := fn(ctx.Request.Context(), arg.Interface().(Req))
+		if err != nil {
+			return nil, err
+		}
+
+		return toResponse(resp), nil
+	}
+
+	return c.addHandler(h, opts...)
+}
+
+// MustHandle is like Handle but panics if the handler can not be registered.
+func MustHandle[Req, Resp any](c *Controller, method, path string, fn TypedHandlerFunc[Req, Resp], opts ...HandlerOption) {
+	if err := Handle(c, method, path, fn, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// toResponse wraps the handler result into a response, results implementing Response are sent as is.
+func toResponse(body any) Response {
+	switch b := body.(type) {
+	case nil:
+		return OKResponse()
+	case Response:
+		return b
+	}
+	return response{}.fromValue(reflect.ValueOf(body))
+}
@@ -0,0 +1,181 @@
+package ginx
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/paveldanilin/ginx/openapi"
+	"github.com/paveldanilin/ginx/resolver"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// FieldError describes a single argument validation failure.
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`
+	Rule    string `json:"rule" xml:"rule"`
+	Param   string `json:"param,omitempty" xml:"param,omitempty"`
+	Value   any    `json:"value,omitempty" xml:"value,omitempty"`
+	Message string `json:"message" xml:"message"`
+}
+
+// ValidationError is returned when resolved handler arguments do not pass validation.
+// It is responded with 422 Unprocessable Entity listing every field error.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Message)
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// StatusCode returns 422 Unprocessable Entity.
+func (e *ValidationError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// ResponseBody returns the error representation sent to the client.
+func (e *ValidationError) ResponseBody() any {
+	return validationErrorBody{Message: "validation failed", Errors: e.Fields}
+}
+
+type validationErrorBody struct {
+	XMLName xml.Name     `json:"-" xml:"error"`
+	Message string       `json:"message" xml:"message"`
+	Errors  []FieldError `json:"errors" xml:"errors>error"`
+}
+
+// validationRule is implemented by resolvers which declare a validation rule for the resolved value, i.e. resolver.Value.
+type validationRule interface {
+	Variable() string
+	ValidationRule() string
+}
+
+type validation struct {
+	tag string
+	fn  validator.Func
+}
+
+// Validation registers a custom validation function available by the tag in 'validate' rules.
+//
+//	controller.Use(ginx.Validation("sku", func(fl validator.FieldLevel) bool {
+//		return strings.HasPrefix(fl.Field().String(), "SKU-")
+//	}))
+func Validation(tag string, fn validator.Func) Option {
+	return validation{tag: tag, fn: fn}
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(fieldName)
+	return v
+}
+
+// fieldName reports a field by the request variable it is bound to, or by its json name.
+func fieldName(field reflect.StructField) string {
+	for _, entry := range strings.Split(field.Tag.Get("ginx"), ",") {
+		if _, variable, isParam := strings.Cut(entry, "="); isParam {
+			return variable
+		}
+	}
+
+	if name, omitted := openapi.JSONFieldName(field); !omitted {
+		return name
+	}
+
+	return field.Name
+}
+
+// validateArguments validates struct arguments by 'validate' tags and scalar arguments by resolver rules.
+func (c *Controller) validateArguments(args []reflect.Value, resolvers []ArgumentResolver) error {
+	var fields []FieldError
+
+	for i, arg := range args {
+		if r, hasRule := resolvers[i].(validationRule); hasRule && r.ValidationRule() != "" {
+			argFields, err := toFieldErrors(c.validator.Var(arg.Interface(), r.ValidationRule()), r.Variable())
+			if err != nil {
+				return err
+			}
+			fields = append(fields, argFields...)
+			continue
+		}
+
+		// The validator does not validate time.Time as a struct.
+		if arg.Kind() == reflect.Struct && arg.Type() != resolver.TimeType {
+			argFields, err := toFieldErrors(c.validator.Struct(arg.Interface()), "")
+			if err != nil {
+				return err
+			}
+			fields = append(fields, argFields...)
+		}
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// verifyValidationRules checks the rules of planned argument resolvers against zero values of the arguments,
+// so a malformed rule fails the registration instead of every request.
+// Custom validations must be registered before the handlers using them.
+func (c *Controller) verifyValidationRules(h *handler) error {
+	for i, argumentType := range h.arguments {
+		r, hasRule := h.plan[i].(validationRule)
+		if !hasRule || r.ValidationRule() == "" {
+			continue
+		}
+		if err := c.checkValidationRule(argumentType, r.ValidationRule()); err != nil {
+			return fmt.Errorf("handler %s: invalid validation rule '%s' of argument at position [%d]: %w", h.name, r.ValidationRule(), i+1, err)
+		}
+	}
+	return nil
+}
+
+// checkValidationRule validates the zero value of the type by the rule, the validator panics on malformed rules.
+func (c *Controller) checkValidationRule(t reflect.Type, rule string) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%v", p)
+		}
+	}()
+
+	_, err = toFieldErrors(c.validator.Var(reflect.Zero(t).Interface(), rule), "")
+	return err
+}
+
+// toFieldErrors converts validation failures to field errors, other errors are returned as is.
+func toFieldErrors(err error, variable string) ([]FieldError, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil, err
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		field := variable
+		if field == "" {
+			// Drop the struct name: "order.items[0].id" -> "items[0].id".
+			_, field, _ = strings.Cut(fe.Namespace(), ".")
+		}
+
+		fields = append(fields, FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Value:   fe.Value(),
+			Message: fmt.Sprintf("'%s' failed on the '%s' rule", field, fe.Tag()),
+		})
+	}
+	return fields, nil
+}
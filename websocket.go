@@ -0,0 +1,256 @@
+package ginx
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/paveldanilin/ginx/requestbody"
+	"github.com/paveldanilin/ginx/resolver"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+var connType = reflect.TypeOf((*Conn)(nil))
+
+// ErrNotWebSocketHandshake is responded to requests of WebSocket endpoints which do not ask for the protocol upgrade.
+var ErrNotWebSocketHandshake = NewHTTPError(http.StatusBadRequest, "not a websocket handshake").WithCode("not_websocket_handshake")
+
+// Conn is a WebSocket connection sending and receiving values encoded by the handler content type, see Controller.WS.
+type Conn struct {
+	ws          *websocket.Conn
+	contentType string
+	renderer    Renderer
+	decoder     requestbody.BodyDecoder
+}
+
+// ContentType returns the media type of values sent and received over the connection.
+func (c *Conn) ContentType() string {
+	return c.contentType
+}
+
+// Receive reads the next message into v, io.EOF is returned when the client closes the connection.
+// Messages are read into *string and *[]byte as is, otherwise they are decoded by the content type decoder.
+func (c *Conn) Receive(v any) error {
+	var data []byte
+	if err := websocket.Message.Receive(c.ws, &data); err != nil {
+		return err
+	}
+
+	switch p := v.(type) {
+	case *string:
+		*p = string(data)
+		return nil
+	case *[]byte:
+		*p = data
+		return nil
+	}
+
+	if err := c.decoder.Decode(bytes.NewReader(data), v); err != nil {
+		return &resolver.BindingError{Scope: resolver.ScopeBody, Type: reflect.TypeOf(v), Err: err}
+	}
+	return nil
+}
+
+// Send writes v as a message, strings are sent as text messages and byte slices as binary messages.
+// Other values are encoded by the content type renderer and sent as text messages if the content type is textual.
+// Send is safe to call concurrently with other Send calls.
+func (c *Conn) Send(v any) error {
+	switch data := v.(type) {
+	case string:
+		return websocket.Message.Send(c.ws, data)
+	case []byte:
+		return websocket.Message.Send(c.ws, data)
+	}
+
+	var buf bytes.Buffer
+	if err := c.renderer.Render(&buf, v); err != nil {
+		return err
+	}
+
+	if mediaType, _ := parseMediaType(c.contentType); isTextMediaType(mediaType) {
+		return websocket.Message.Send(c.ws, buf.String())
+	}
+	return websocket.Message.Send(c.ws, buf.Bytes())
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.ws.Close()
+}
+
+// CheckOrigin sets a function accepting or rejecting the WebSocket handshake by the request Origin header.
+// By default, requests without the Origin header and requests from the same host are accepted.
+//
+//	controller.WS("/ws/chat", chat, ginx.CheckOrigin(func(r *http.Request) bool {
+//		return r.Header.Get("Origin") == "https://chat.example.com"
+//	}))
+func CheckOrigin(fn func(r *http.Request) bool) func(*handler) {
+	return func(h *handler) {
+		h.checkOrigin = fn
+	}
+}
+
+// WS registers a WebSocket endpoint.
+// The handler takes *Conn along with arguments injected by resolvers, like path variables or services,
+// and may return an error which is reported to gin as the connection is closed.
+// Arguments are resolved and validated before the handshake, so their errors are responded as usual.
+// Values are encoded by the handler content type (see Produce) or the controller ContentType, JSON by default.
+//
+//	type chatRoom struct {
+//		ginx.Path[string] `ginx:"room"`
+//	}
+//
+//	controller.WS("/ws/chat/:room", func(conn *ginx.Conn, room chatRoom, hub *Hub) error {
+//		for {
+//			var msg Message
+//			if err := conn.Receive(&msg); err != nil {
+//				return err
+//			}
+//			hub.Broadcast(room.Value, msg)
+//		}
+//	})
+func (c *Controller) WS(path string, handler HandlerFunc, opts ...HandlerOption) error {
+	h, err := c.newHandler("GET", path, handler)
+	if err != nil {
+		return err
+	}
+	h.websocket = true
+
+	h.init(c.allArgumentResolvers(), append([]HandlerOption{&connResolver{}}, opts...)...)
+
+	return c.addHandler(h, opts...)
+}
+
+// MustWS is like WS but panics if the handler can not be registered.
+func (c *Controller) MustWS(path string, handler HandlerFunc, opts ...HandlerOption) {
+	if err := c.WS(path, handler, opts...); err != nil {
+		panic(err)
+	}
+}
+
+func (c *Controller) handleWebSocket(ctx *gin.Context, h *handler) {
+	defer func() {
+		if err := recover(); err != nil {
+			c.handlePanic(ctx, err)
+		}
+	}()
+
+	ctx.Set("ginx_handler_name", h.name)
+
+	if !isWebSocketHandshake(ctx.Request) {
+		c.handleError(ctx, ErrNotWebSocketHandshake)
+		return
+	}
+
+	conn, err := c.newConn(h)
+	if err != nil {
+		panic(err)
+	}
+	ctx.Set("ginx_websocket_conn", conn)
+
+	hArgs, hResolvers, err := h.resolveArguments(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := c.validateArguments(hArgs, hResolvers); err != nil {
+		panic(err)
+	}
+
+	checkOrigin := h.checkOrigin
+	if checkOrigin == nil {
+		checkOrigin = isSameOrigin
+	}
+
+	server := websocket.Server{
+		Handshake: func(_ *websocket.Config, req *http.Request) error {
+			if !checkOrigin(req) {
+				return fmt.Errorf("origin '%s' is not allowed", req.Header.Get("Origin"))
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			// The connection is hijacked, errors can not be responded anymore.
+			defer func() {
+				if err := recover(); err != nil {
+					_ = ctx.Error(fmt.Errorf("websocket handler %s: %v", h.name, err))
+				}
+			}()
+
+			conn.ws = ws
+			out := h.function.Call(hArgs)
+			if len(out) > 0 && isError(out[0]) {
+				_ = ctx.Error(out[0].Interface().(error))
+			}
+		},
+	}
+	server.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// newConn creates a connection exchanging values by the first content type the handler produces.
+func (c *Controller) newConn(h *handler) (*Conn, error) {
+	contentType := gin.MIMEJSON
+	if offers := c.producibleContentTypes(h); len(offers) > 0 {
+		contentType = offers[0]
+	}
+
+	r := c.findRenderer(contentType)
+	if r == nil {
+		return nil, fmt.Errorf("renderer not found for websocket content type '%s'", contentType)
+	}
+
+	decoder, exists := requestbody.DecoderFor(contentType)
+	if !exists {
+		return nil, fmt.Errorf("decoder not found for websocket content type '%s'", contentType)
+	}
+
+	return &Conn{contentType: contentType, renderer: r, decoder: decoder}, nil
+}
+
+// isWebSocketHandshake reports whether the request asks for the upgrade to the WebSocket protocol.
+func isWebSocketHandshake(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, token := range strings.Split(r.Header.Get("Connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// isSameOrigin accepts requests without the Origin header, i.e. from non-browser clients, or from the same host.
+func isSameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// connResolver injects *Conn into WebSocket handlers, the connection is established after the arguments are resolved.
+type connResolver struct{}
+
+func (r *connResolver) Priority() int {
+	return 300
+}
+
+func (r *connResolver) Static() bool {
+	return true
+}
+
+func (r *connResolver) CanResolve(_ *gin.Context, argumentType reflect.Type, _ int) bool {
+	return argumentType == connType
+}
+
+func (r *connResolver) Resolve(ctx *gin.Context, _ reflect.Type) (reflect.Value, error) {
+	return reflect.ValueOf(ctx.MustGet("ginx_websocket_conn")), nil
+}